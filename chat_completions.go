@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 )
 
 type ChatCompletionsRequest struct {
@@ -31,9 +32,68 @@ type ChatCompletionsRequest struct {
 	MinP              float64              `json:"min_p"`
 }
 
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleTool      = "tool"
+)
+
 type Message struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role         string        `json:"role"`
+	Content      string        `json:"content"`
+	ContentParts []ContentPart `json:"-"`                      // Sent in place of Content when set, e.g. for images.
+	ToolCalls    []ToolCall    `json:"tool_calls,omitempty"`   // Tool calls requested by the assistant.
+	ToolCallID   string        `json:"tool_call_id,omitempty"` // ID of the tool call a tool message responds to.
+	Name         string        `json:"name,omitempty"`
+}
+
+// MarshalJSON encodes the content as an array of parts when ContentParts is
+// set, and as a plain string otherwise.
+func (m Message) MarshalJSON() ([]byte, error) {
+	type message Message
+	if len(m.ContentParts) == 0 {
+		return json.Marshal(message(m))
+	}
+
+	return json.Marshal(struct {
+		message
+		Content []ContentPart `json:"content"`
+	}{message(m), m.ContentParts})
+}
+
+// UnmarshalJSON accepts content as either a string or an array of parts. For
+// an array, Content is set to the concatenated text parts.
+func (m *Message) UnmarshalJSON(data []byte) error {
+	type message Message
+	raw := struct {
+		*message
+		Content json.RawMessage `json:"content"`
+	}{message: (*message)(m)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	m.Content = ""
+	m.ContentParts = nil
+
+	content := bytes.TrimSpace(raw.Content)
+	switch {
+	case len(content) == 0 || string(content) == "null":
+		return nil
+	case content[0] == '[':
+		if err := json.Unmarshal(content, &m.ContentParts); err != nil {
+			return err
+		}
+		var text strings.Builder
+		for _, part := range m.ContentParts {
+			text.WriteString(part.Text)
+		}
+		m.Content = text.String()
+		return nil
+	default:
+		return json.Unmarshal(content, &m.Content)
+	}
 }
 
 type ResponseFormatObject struct {
@@ -52,18 +112,80 @@ type FunctionObject struct {
 	Parameters  json.RawMessage `json:"parameters"` // maybe should be *json.RawMessage ?
 }
 
+type ToolCall struct {
+	Index    int          `json:"index,omitempty"` // Only set on streamed tool call fragments.
+	Id       string       `json:"id,omitempty"`
+	Type     string       `json:"type,omitempty"`
+	Function FunctionCall `json:"function"`
+}
+
+type FunctionCall struct {
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments"` // JSON encoded arguments generated by the model.
+}
+
+// UnmarshalArguments decodes the JSON arguments of the function call into v.
+func (f FunctionCall) UnmarshalArguments(v any) error {
+	if f.Arguments == "" {
+		return json.Unmarshal([]byte("{}"), v)
+	}
+	if err := json.Unmarshal([]byte(f.Arguments), v); err != nil {
+		return fmt.Errorf("invalid arguments for function %q: %w", f.Name, err)
+	}
+	return nil
+}
+
 type ToolChoiceObject struct {
 	Type     string         `json:"type"`
 	Function FunctionObject `json:"function"`
 }
 
 type ChatCompletionsResponse struct {
-	Id      string      `json:"id"`
-	Choices []Message   `json:"choices"`
-	Usage   UsageObject `json:"usage"`
-	Created int         `json:"created"`
-	Model   string      `json:"model"`
-	Object  string      `json:"object"`
+	Id      string       `json:"id"`
+	Choices []ChatChoice `json:"choices"`
+	Usage   UsageObject  `json:"usage"`
+	Created int          `json:"created"`
+	Model   string       `json:"model"`
+	Object  string       `json:"object"`
+}
+
+const (
+	FinishReasonStop      = "stop"
+	FinishReasonEOS       = "eos"
+	FinishReasonLength    = "length"
+	FinishReasonToolCalls = "tool_calls"
+)
+
+type ChatChoice struct {
+	Index        int       `json:"index"`
+	Message      Message   `json:"message"`
+	FinishReason string    `json:"finish_reason"`
+	Logprobs     *Logprobs `json:"logprobs"` // Only set when logprobs are requested.
+}
+
+// Truncated reports whether generation stopped because the token limit was reached.
+func (c ChatChoice) Truncated() bool {
+	return c.FinishReason == FinishReasonLength
+}
+
+// FirstChoice returns the first choice of the response, if any.
+func (r ChatCompletionsResponse) FirstChoice() (ChatChoice, bool) {
+	if len(r.Choices) == 0 {
+		return ChatChoice{}, false
+	}
+	return r.Choices[0], true
+}
+
+// Content returns the message content of the first choice.
+func (r ChatCompletionsResponse) Content() string {
+	choice, _ := r.FirstChoice()
+	return choice.Message.Content
+}
+
+// ToolCalls returns the tool calls requested in the first choice.
+func (r ChatCompletionsResponse) ToolCalls() []ToolCall {
+	choice, _ := r.FirstChoice()
+	return choice.Message.ToolCalls
 }
 
 type UsageObject struct {
@@ -106,7 +228,7 @@ func (api *API) ChatCompletions(ctx context.Context, model string, messages []Me
 		return ChatCompletionsResponse{}, err
 	}
 	if res.StatusCode != http.StatusOK {
-		return ChatCompletionsResponse{}, fmt.Errorf("HTTP request failed: %s", string(body))
+		return ChatCompletionsResponse{}, newAPIError(res, body)
 	}
 
 	var chatCompletionsResponse ChatCompletionsResponse
@@ -117,3 +239,69 @@ func (api *API) ChatCompletions(ctx context.Context, model string, messages []Me
 
 	return chatCompletionsResponse, nil
 }
+
+type ChatCompletionsChunk struct {
+	Id      string                       `json:"id"`
+	Choices []ChatCompletionsChunkChoice `json:"choices"`
+	Usage   *UsageObject                 `json:"usage"` // Only set on the final chunk.
+	Created int                          `json:"created"`
+	Model   string                       `json:"model"`
+	Object  string                       `json:"object"`
+}
+
+type ChatCompletionsChunkChoice struct {
+	Index        int       `json:"index"`
+	Delta        ChatDelta `json:"delta"`
+	FinishReason string    `json:"finish_reason"`
+	Logprobs     *Logprobs `json:"logprobs"` // Only set when logprobs are requested.
+}
+
+type ChatDelta struct {
+	Role      string     `json:"role"`
+	Content   string     `json:"content"`
+	ToolCalls []ToolCall `json:"tool_calls"` // Fragments are keyed by Index and must be concatenated by the caller.
+}
+
+// Chat Completions Stream is the streaming variant of the Chat Completions endpoint.
+// Chunks are returned by calling Recv on the returned stream until it returns io.EOF.
+//
+// API Reference: https://docs.together.ai/reference/chat-completions
+func (api *API) ChatCompletionsStream(ctx context.Context, model string, messages []Message, request ChatCompletionsRequest) (*Stream[ChatCompletionsChunk], error) {
+	if len(messages) == 0 || messages == nil {
+		return nil, fmt.Errorf("no messages provided")
+	}
+	if model == "" {
+		return nil, fmt.Errorf("no model provided")
+	}
+	if ctx == nil {
+		return nil, fmt.Errorf("no context provided")
+	}
+
+	request.Messages = messages
+	request.Model = model
+	request.Stream = true
+
+	uri := defaultBasePath + Version + "/chat/completions"
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make(http.Header)
+	headers.Set("Accept", "text/event-stream")
+
+	res, err := api.request(ctx, "POST", uri, bytes.NewBuffer(reqBody), headers)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, newAPIError(res, body)
+	}
+
+	return newStream[ChatCompletionsChunk](ctx, res.Body), nil
+}
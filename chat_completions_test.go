@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
@@ -27,7 +29,7 @@ func TestChatCompletions(t *testing.T) {
 	}
 
 	// Case: Chat Completion Fails with no model
-	resp, err = req.ChatCompletions(context.TODO(), "", []Message{{"Role", "Content"}}, ChatCompletionsRequest{})
+	resp, err = req.ChatCompletions(context.TODO(), "", []Message{{Role: "Role", Content: "Content"}}, ChatCompletionsRequest{})
 	if !reflect.DeepEqual(resp, ChatCompletionsResponse{}) {
 		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, ChatCompletionsResponse{})
 	}
@@ -36,7 +38,7 @@ func TestChatCompletions(t *testing.T) {
 	}
 
 	// Case: Chat Completion Fails with no context provided
-	resp, err = req.ChatCompletions(nil, "a", []Message{{"Role", "Content"}}, ChatCompletionsRequest{}) //lint:ignore SA1012 nil context used intentionally
+	resp, err = req.ChatCompletions(nil, "a", []Message{{Role: "Role", Content: "Content"}}, ChatCompletionsRequest{}) //lint:ignore SA1012 nil context used intentionally
 	if !reflect.DeepEqual(resp, ChatCompletionsResponse{}) {
 		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, ChatCompletionsResponse{})
 	}
@@ -45,7 +47,7 @@ func TestChatCompletions(t *testing.T) {
 	}
 
 	// Case: Chat Completion Fails with invalid HTTP request
-	resp, err = req.ChatCompletions(context.TODO(), "a", []Message{{"Role", "Content"}}, ChatCompletionsRequest{})
+	resp, err = req.ChatCompletions(context.TODO(), "a", []Message{{Role: "Role", Content: "Content"}}, ChatCompletionsRequest{})
 	if !reflect.DeepEqual(resp, ChatCompletionsResponse{}) {
 		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, ChatCompletionsResponse{})
 	}
@@ -60,7 +62,7 @@ func TestChatCompletions(t *testing.T) {
 
 	req.BaseURL = ts.URL
 
-	resp, err = req.ChatCompletions(context.TODO(), "a", []Message{{"Role", "Content"}}, ChatCompletionsRequest{})
+	resp, err = req.ChatCompletions(context.TODO(), "a", []Message{{Role: "Role", Content: "Content"}}, ChatCompletionsRequest{})
 	if !reflect.DeepEqual(resp, ChatCompletionsResponse{}) {
 		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, ChatCompletionsResponse{})
 	}
@@ -78,7 +80,7 @@ func TestChatCompletions(t *testing.T) {
 
 	req.BaseURL = ts.URL
 
-	resp, err = req.ChatCompletions(context.TODO(), "a", []Message{{"Role", "Content"}}, ChatCompletionsRequest{})
+	resp, err = req.ChatCompletions(context.TODO(), "a", []Message{{Role: "Role", Content: "Content"}}, ChatCompletionsRequest{})
 	if !reflect.DeepEqual(resp, ChatCompletionsResponse{}) {
 		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, ChatCompletionsResponse{})
 	}
@@ -97,7 +99,7 @@ func TestChatCompletions(t *testing.T) {
 
 	req.BaseURL = ts.URL
 
-	resp, err = req.ChatCompletions(context.TODO(), "a", []Message{{"Role", "Content"}}, ChatCompletionsRequest{})
+	resp, err = req.ChatCompletions(context.TODO(), "a", []Message{{Role: "Role", Content: "Content"}}, ChatCompletionsRequest{})
 	if !reflect.DeepEqual(resp, ChatCompletionsResponse{}) {
 		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, ChatCompletionsResponse{})
 	}
@@ -107,3 +109,187 @@ func TestChatCompletions(t *testing.T) {
 
 	ts.Close()
 }
+
+func TestChatCompletionsStream(t *testing.T) {
+	req, _ := New("hunter2")
+	req.Client.RetryMax = 1
+	req.BaseURL = ""
+	req.Debug = false
+
+	// Case: Chat Completions Stream Fails with no message
+	stream, err := req.ChatCompletionsStream(context.TODO(), "", nil, ChatCompletionsRequest{})
+	if stream != nil {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", stream, nil)
+	}
+	if err == nil {
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "no messages provided")
+	}
+
+	// Case: Chat Completions Stream Fails with no model
+	stream, err = req.ChatCompletionsStream(context.TODO(), "", []Message{{Role: "Role", Content: "Content"}}, ChatCompletionsRequest{})
+	if stream != nil {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", stream, nil)
+	}
+	if err == nil {
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "no model provided")
+	}
+
+	// Case: Chat Completions Stream Fails with no context provided
+	stream, err = req.ChatCompletionsStream(nil, "a", []Message{{Role: "Role", Content: "Content"}}, ChatCompletionsRequest{}) //lint:ignore SA1012 nil context used intentionally
+	if stream != nil {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", stream, nil)
+	}
+	if err == nil {
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "no context provided")
+	}
+
+	// Case: Chat Completions Stream Fails with HTTP 400
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(400)
+		fmt.Fprintln(w, nil)
+	}))
+
+	req.BaseURL = ts.URL
+
+	stream, err = req.ChatCompletionsStream(context.TODO(), "a", []Message{{Role: "Role", Content: "Content"}}, ChatCompletionsRequest{})
+	if stream != nil {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", stream, nil)
+	}
+	if err == nil {
+		t.Errorf("Error was incorrect, got: %s, want: %v.", err, nil)
+	}
+
+	ts.Close()
+
+	// Case: Chat Completions Stream Succeeds with HTTP 200
+	req.Debug = true
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body ChatCompletionsRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if !body.Stream {
+			t.Errorf("Result was incorrect, got: %v, want: %v.", body.Stream, true)
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\",\"content\":\"Hello\"}}]}\n\n")
+		fmt.Fprint(w, ": keep-alive\n\n")
+		fmt.Fprint(w, "data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{\"tool_calls\":[{\"index\":0,\"id\":\"call_1\",\"function\":{\"name\":\"get\",\"arguments\":\"{}\"}}]}}]}\n\n")
+		fmt.Fprint(w, "data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\" world\"},\"finish_reason\":\"stop\"}],\"usage\":{\"prompt_tokens\":1,\"completion_tokens\":2,\"total_tokens\":3}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+
+	req.BaseURL = ts.URL
+
+	stream, err = req.ChatCompletionsStream(context.TODO(), "a", []Message{{Role: "Role", Content: "Content"}}, ChatCompletionsRequest{})
+	if err != nil {
+		t.Fatalf("Error was incorrect, got: %s, want: %v.", err, nil)
+	}
+
+	var content string
+	var chunks []ChatCompletionsChunk
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Error was incorrect, got: %s, want: %v.", err, nil)
+		}
+		chunks = append(chunks, chunk)
+		content += chunk.Choices[0].Delta.Content
+	}
+	stream.Close()
+
+	if len(chunks) != 3 {
+		t.Errorf("Result was incorrect, got: %d, want: %d.", len(chunks), 3)
+	}
+	if content != "Hello world" {
+		t.Errorf("Result was incorrect, got: %s, want: %s.", content, "Hello world")
+	}
+	if chunks[1].Choices[0].Delta.ToolCalls[0].Function.Name != "get" {
+		t.Errorf("Result was incorrect, got: %s, want: %s.", chunks[1].Choices[0].Delta.ToolCalls[0].Function.Name, "get")
+	}
+	if chunks[2].Choices[0].FinishReason != "stop" {
+		t.Errorf("Result was incorrect, got: %s, want: %s.", chunks[2].Choices[0].FinishReason, "stop")
+	}
+	if chunks[2].Usage == nil || chunks[2].Usage.TotalTokens != 3 {
+		t.Errorf("Result was incorrect, got: %v, want: %d.", chunks[2].Usage, 3)
+	}
+
+	ts.Close()
+}
+
+// Test encoding tool calls and tool replies in messages.
+func TestMessageToolCalls(t *testing.T) {
+	// Case: Plain messages omit tool fields
+	b, _ := json.Marshal(Message{Role: RoleUser, Content: "hi"})
+	if string(b) != `{"role":"user","content":"hi"}` {
+		t.Errorf("Result was incorrect, got: %s, want: %s.", b, `{"role":"user","content":"hi"}`)
+	}
+
+	// Case: Assistant tool calls round trip and arguments decode
+	var msg Message
+	err := json.Unmarshal([]byte(`{"role":"assistant","content":null,"tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Oslo\"}"}}]}`), &msg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(msg.ToolCalls) != 1 || msg.ToolCalls[0].Id != "call_1" || msg.ToolCalls[0].Function.Name != "get_weather" {
+		t.Errorf("Result was incorrect, got: %+v.", msg)
+	}
+
+	var args struct {
+		City string `json:"city"`
+	}
+	if err := msg.ToolCalls[0].Function.UnmarshalArguments(&args); err != nil || args.City != "Oslo" {
+		t.Errorf("Result was incorrect, got: %v %v, want: %s.", args, err, "Oslo")
+	}
+
+	// Case: Invalid arguments are reported
+	if err := (FunctionCall{Name: "f", Arguments: "{"}).UnmarshalArguments(&args); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "invalid arguments")
+	}
+
+	// Case: Tool replies carry the tool call ID
+	b, _ = json.Marshal(Message{Role: RoleTool, Content: "sunny", ToolCallID: "call_1", Name: "get_weather"})
+	if string(b) != `{"role":"tool","content":"sunny","tool_call_id":"call_1","name":"get_weather"}` {
+		t.Errorf("Result was incorrect, got: %s.", b)
+	}
+}
+
+// Test decoding choices from a chat completions response.
+func TestChatCompletionsChoices(t *testing.T) {
+	req, _ := New("hunter2")
+	req.Client.RetryMax = 1
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"id":"1","object":"chat.completion","choices":[{"index":0,"message":{"role":"assistant","content":"Hello","tool_calls":[{"id":"call_1","type":"function","function":{"name":"f","arguments":"{}"}}]},"finish_reason":"length","logprobs":{"tokens":["Hello"],"token_logprobs":[-0.1]}}],"usage":{"total_tokens":3}}`)
+	}))
+	defer ts.Close()
+
+	req.BaseURL = ts.URL
+
+	resp, err := req.ChatCompletions(context.TODO(), "a", []Message{{Role: RoleUser, Content: "hi"}}, ChatCompletionsRequest{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if resp.Content() != "Hello" {
+		t.Errorf("Result was incorrect, got: %s, want: %s.", resp.Content(), "Hello")
+	}
+	if len(resp.ToolCalls()) != 1 || resp.ToolCalls()[0].Function.Name != "f" {
+		t.Errorf("Result was incorrect, got: %v.", resp.ToolCalls())
+	}
+
+	choice, ok := resp.FirstChoice()
+	if !ok || !choice.Truncated() {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", choice.FinishReason, FinishReasonLength)
+	}
+	if choice.Logprobs == nil || !reflect.DeepEqual(choice.Logprobs.TokenLogprobs, []float64{-0.1}) {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", choice.Logprobs, []float64{-0.1})
+	}
+
+	// Case: Empty responses have no content
+	if (ChatCompletionsResponse{}).Content() != "" {
+		t.Errorf("Result was incorrect, got: %s, want: %s.", (ChatCompletionsResponse{}).Content(), "")
+	}
+}
@@ -35,7 +35,37 @@ type CompletionsResponse struct {
 }
 
 type ChoiceObject struct {
-	Text string `json:"text"`
+	Index        int       `json:"index"`
+	Text         string    `json:"text"`
+	FinishReason string    `json:"finish_reason"`
+	Logprobs     *Logprobs `json:"logprobs"` // Only set when logprobs are requested.
+}
+
+type Logprobs struct {
+	Tokens        []string  `json:"tokens"`
+	TokenLogprobs []float64 `json:"token_logprobs"`
+}
+
+// UnmarshalJSON accepts both the logprobs object and the bare per-token
+// logprob that is sent with each streamed chunk.
+func (l *Logprobs) UnmarshalJSON(data []byte) error {
+	var logprob float64
+	if err := json.Unmarshal(data, &logprob); err == nil {
+		l.TokenLogprobs = []float64{logprob}
+		return nil
+	}
+
+	type logprobs Logprobs
+	return json.Unmarshal(data, (*logprobs)(l))
+}
+
+type CompletionsChunk struct {
+	Id      string         `json:"id"`
+	Choices []ChoiceObject `json:"choices"`
+	Usage   *UsageObject   `json:"usage"` // Only set on the final chunk.
+	Created int            `json:"created"`
+	Model   string         `json:"model"`
+	Object  string         `json:"object"`
 }
 
 // Completions is the endpoint for language, code, and image models on Together AI.
@@ -76,7 +106,7 @@ func (api *API) Completions(ctx context.Context, model string, prompt string, ma
 		return CompletionsResponse{}, err
 	}
 	if res.StatusCode != http.StatusOK {
-		return CompletionsResponse{}, fmt.Errorf("HTTP request failed: %s", string(body))
+		return CompletionsResponse{}, newAPIError(res, body)
 	}
 
 	var completionsResponse CompletionsResponse
@@ -87,3 +117,51 @@ func (api *API) Completions(ctx context.Context, model string, prompt string, ma
 
 	return completionsResponse, nil
 }
+
+// Completions Stream is the streaming variant of the Completions endpoint.
+// Chunks are returned by calling Recv on the returned stream until it returns io.EOF.
+//
+// API Reference: https://docs.together.ai/reference/completions
+func (api *API) CompletionsStream(ctx context.Context, model string, prompt string, maxTokens int32, request CompletionsRequest) (*Stream[CompletionsChunk], error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("no context provided")
+	}
+	if model == "" {
+		return nil, fmt.Errorf("no model provided")
+	}
+	if prompt == "" {
+		return nil, fmt.Errorf("no prompt provided")
+	}
+	if maxTokens < 1 {
+		return nil, fmt.Errorf("maxTokens must be greater than 0 and less than 2147483647")
+	}
+
+	request.Model = model
+	request.Prompt = prompt
+	request.MaxTokens = maxTokens
+	request.Stream = true
+
+	uri := defaultBasePath + Version + "/completions"
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make(http.Header)
+	headers.Set("Accept", "text/event-stream")
+
+	res, err := api.request(ctx, "POST", uri, bytes.NewBuffer(reqBody), headers)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, newAPIError(res, body)
+	}
+
+	return newStream[CompletionsChunk](ctx, res.Body), nil
+}
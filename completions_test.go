@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
@@ -117,3 +119,114 @@ func TestCompletions(t *testing.T) {
 	ts.Close()
 
 }
+
+func TestCompletionsStream(t *testing.T) {
+	req, _ := New("hunter2")
+	req.Client.RetryMax = 1
+	req.BaseURL = ""
+	req.Debug = false
+
+	// Case: Completions Stream Fails with no context
+	stream, err := req.CompletionsStream(nil, "", "", 0, CompletionsRequest{}) //lint:ignore SA1012 nil context used intentionally
+	if stream != nil {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", stream, nil)
+	}
+	if err == nil {
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "no context provided")
+	}
+
+	// Case: Completions Stream Fails with no tokens
+	stream, err = req.CompletionsStream(context.TODO(), "a", "b", 0, CompletionsRequest{})
+	if stream != nil {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", stream, nil)
+	}
+	if err == nil {
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "maxTokens must be greater than 0 and less than 2147483647")
+	}
+
+	// Case: Completions Stream Fails with HTTP 400
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(400)
+		fmt.Fprintln(w, nil)
+	}))
+
+	req.BaseURL = ts.URL
+
+	stream, err = req.CompletionsStream(context.TODO(), "a", "b", 10, CompletionsRequest{})
+	if stream != nil {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", stream, nil)
+	}
+	if err == nil {
+		t.Errorf("Error was incorrect, got: %s, want: %v.", err, nil)
+	}
+
+	ts.Close()
+
+	// Case: Completions Stream Succeeds with HTTP 200 and logprobs
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"text\":\"Hello\",\"logprobs\":-0.5}]}\n\n")
+		fmt.Fprint(w, "data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"text\":\" world\",\"logprobs\":{\"tokens\":[\" world\"],\"token_logprobs\":[-0.25]},\"finish_reason\":\"length\"}],\"usage\":{\"total_tokens\":3}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+
+	req.BaseURL = ts.URL
+
+	stream, err = req.CompletionsStream(context.TODO(), "a", "b", 10, CompletionsRequest{Logprobs: 1})
+	if err != nil {
+		t.Fatalf("Error was incorrect, got: %s, want: %v.", err, nil)
+	}
+
+	var text string
+	var logprobs []float64
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Error was incorrect, got: %s, want: %v.", err, nil)
+		}
+		text += chunk.Choices[0].Text
+		logprobs = append(logprobs, chunk.Choices[0].Logprobs.TokenLogprobs...)
+	}
+	stream.Close()
+
+	if text != "Hello world" {
+		t.Errorf("Result was incorrect, got: %s, want: %s.", text, "Hello world")
+	}
+	if !reflect.DeepEqual(logprobs, []float64{-0.5, -0.25}) {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", logprobs, []float64{-0.5, -0.25})
+	}
+
+	ts.Close()
+
+	// Case: Completions Stream is aborted by cancelling the context
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"text\":\"Hello\"}]}\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+
+	req.BaseURL = ts.URL
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err = req.CompletionsStream(ctx, "a", "b", 10, CompletionsRequest{})
+	if err != nil {
+		t.Fatalf("Error was incorrect, got: %s, want: %v.", err, nil)
+	}
+
+	_, err = stream.Recv()
+	if err != nil {
+		t.Errorf("Error was incorrect, got: %s, want: %v.", err, nil)
+	}
+	cancel()
+	_, err = stream.Recv()
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, context.Canceled)
+	}
+	stream.Close()
+
+	ts.Close()
+}
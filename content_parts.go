@@ -0,0 +1,71 @@
+package together
+
+import (
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	ContentPartText     = "text"
+	ContentPartImageURL = "image_url"
+
+	ImageDetailAuto = "auto"
+	ImageDetailLow  = "low"
+	ImageDetailHigh = "high"
+)
+
+// ContentPart is a single part of a multimodal message.
+type ContentPart struct {
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	ImageURL *ImageURL `json:"image_url,omitempty"`
+}
+
+type ImageURL struct {
+	URL    string `json:"url"`              // HTTP(S) URL or base64 data URI.
+	Detail string `json:"detail,omitempty"` // One of auto, low or high.
+}
+
+// TextPart returns a text content part.
+func TextPart(text string) ContentPart {
+	return ContentPart{Type: ContentPartText, Text: text}
+}
+
+// ImageURLPart returns an image content part referencing a URL or data URI.
+func ImageURLPart(url, detail string) ContentPart {
+	return ContentPart{Type: ContentPartImageURL, ImageURL: &ImageURL{URL: url, Detail: detail}}
+}
+
+// ImageDataPart returns an image content part embedding data as a base64 data
+// URI. If mimeType is empty it is detected from the data.
+func ImageDataPart(data []byte, mimeType, detail string) (ContentPart, error) {
+	if len(data) == 0 {
+		return ContentPart{}, fmt.Errorf("no image data provided")
+	}
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+	if !strings.HasPrefix(mimeType, "image/") {
+		return ContentPart{}, fmt.Errorf("unsupported image type %q", mimeType)
+	}
+
+	uri := "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data)
+	return ImageURLPart(uri, detail), nil
+}
+
+// ImageFilePart reads a local image file and returns it as an embedded image
+// content part.
+func ImageFilePart(path, detail string) (ContentPart, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ContentPart{}, err
+	}
+
+	mimeType, _, _ := strings.Cut(mime.TypeByExtension(filepath.Ext(path)), ";")
+	return ImageDataPart(data, mimeType, detail)
+}
@@ -0,0 +1,69 @@
+package together
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Test encoding and decoding multimodal message content.
+func TestMessageContentParts(t *testing.T) {
+	// Case: Parts are sent in place of the string content
+	msg := Message{Role: RoleUser, ContentParts: []ContentPart{TextPart("What is this?"), ImageURLPart("https://example.com/a.png", ImageDetailLow)}}
+	b, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := `{"role":"user","content":[{"type":"text","text":"What is this?"},{"type":"image_url","image_url":{"url":"https://example.com/a.png","detail":"low"}}]}`
+	if string(b) != want {
+		t.Errorf("Result was incorrect, got: %s, want: %s.", b, want)
+	}
+
+	// Case: Parts are decoded and their text is concatenated into Content
+	var decoded Message
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if decoded.Content != "What is this?" || len(decoded.ContentParts) != 2 || decoded.ContentParts[1].ImageURL.Detail != ImageDetailLow {
+		t.Errorf("Result was incorrect, got: %+v.", decoded)
+	}
+
+	// Case: String content still decodes
+	if err := json.Unmarshal([]byte(`{"role":"assistant","content":"hi"}`), &decoded); err != nil || decoded.Content != "hi" || decoded.ContentParts != nil {
+		t.Errorf("Result was incorrect, got: %+v %v.", decoded, err)
+	}
+}
+
+// Test building image content parts from local data.
+func TestImageParts(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+	path := filepath.Join(t.TempDir(), "image.png")
+	if err := os.WriteFile(path, png, 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	part, err := ImageFilePart(path, ImageDetailHigh)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if part.Type != ContentPartImageURL || !strings.HasPrefix(part.ImageURL.URL, "data:image/png;base64,iVBORw0KGgo") || part.ImageURL.Detail != ImageDetailHigh {
+		t.Errorf("Result was incorrect, got: %+v.", part.ImageURL)
+	}
+
+	// Case: Type is detected when not provided
+	part, err = ImageDataPart(png, "", "")
+	if err != nil || !strings.HasPrefix(part.ImageURL.URL, "data:image/png;base64,") {
+		t.Errorf("Result was incorrect, got: %+v %v.", part.ImageURL, err)
+	}
+
+	// Case: Non-image data and missing files are rejected
+	if _, err := ImageDataPart([]byte("hello"), "", ""); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "unsupported image type")
+	}
+	if _, err := ImageFilePart(filepath.Join(t.TempDir(), "missing.png"), ""); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no such file")
+	}
+}
@@ -0,0 +1,233 @@
+package together
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DefaultMaxSampleTokens is the estimated token count above which a sample is
+// reported as too long.
+const DefaultMaxSampleTokens = 8192
+
+// DatasetFormat is the layout of the samples in a JSONL fine-tuning dataset.
+type DatasetFormat string
+
+const (
+	DatasetFormatText         DatasetFormat = "text"         // {"text": "..."}
+	DatasetFormatConversation DatasetFormat = "conversation" // {"messages": [{"role": "user", "content": "..."}, ...]}
+	DatasetFormatInstruction  DatasetFormat = "instruction"  // {"prompt": "...", "completion": "..."}
+)
+
+// DatasetLineError describes an invalid line in a dataset.
+type DatasetLineError struct {
+	Line    int // 1-based line number.
+	Message string
+}
+
+func (e DatasetLineError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+type DatasetReport struct {
+	Format          DatasetFormat      // Format detected from the first valid sample.
+	Lines           int                // Number of lines read.
+	Samples         int                // Number of valid samples.
+	EstimatedTokens int                // Estimated tokens across all valid samples.
+	MaxSampleTokens int                // Estimated tokens of the longest valid sample.
+	Errors          []DatasetLineError // Invalid lines, in order.
+}
+
+// Valid reports whether the dataset contains samples and no invalid lines.
+func (r DatasetReport) Valid() bool {
+	return r.Samples > 0 && len(r.Errors) == 0
+}
+
+// Err returns the invalid lines joined into a single error, or nil if the
+// dataset is valid.
+func (r DatasetReport) Err() error {
+	if r.Samples == 0 && len(r.Errors) == 0 {
+		return errors.New("dataset contains no samples")
+	}
+
+	errs := make([]error, len(r.Errors))
+	for i, err := range r.Errors {
+		errs[i] = err
+	}
+	return errors.Join(errs...)
+}
+
+// DatasetValidator checks JSONL fine-tuning datasets before they are uploaded.
+type DatasetValidator struct {
+	Format          DatasetFormat // Expected format; detected from the first valid sample if empty.
+	MaxSampleTokens int           // Defaults to DefaultMaxSampleTokens.
+}
+
+// ValidateDataset checks a JSONL fine-tuning dataset with the default
+// settings. See DatasetValidator.Validate.
+func ValidateDataset(r io.Reader) (DatasetReport, error) {
+	return DatasetValidator{}.Validate(r)
+}
+
+// Validate reads a JSONL dataset and reports invalid lines together with
+// summary statistics. Every line must be a JSON object in the same format.
+// The returned error is only set if reading fails; problems with the dataset
+// itself are listed in DatasetReport.Errors.
+func (v DatasetValidator) Validate(r io.Reader) (DatasetReport, error) {
+	maxTokens := v.MaxSampleTokens
+	if maxTokens <= 0 {
+		maxTokens = DefaultMaxSampleTokens
+	}
+
+	report := DatasetReport{Format: v.Format}
+	br := bufio.NewReader(r)
+
+	for {
+		// A final line without a newline is returned together with io.EOF.
+		line, readErr := br.ReadBytes('\n')
+		if len(line) == 0 && readErr == io.EOF {
+			break
+		}
+		if readErr != nil && readErr != io.EOF {
+			return report, readErr
+		}
+		report.Lines++
+
+		invalid := func(format string, args ...any) {
+			report.Errors = append(report.Errors, DatasetLineError{Line: report.Lines, Message: fmt.Sprintf(format, args...)})
+		}
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			invalid("empty line")
+			continue
+		}
+
+		var sample map[string]json.RawMessage
+		if err := json.Unmarshal(line, &sample); err != nil {
+			invalid("invalid JSON: %v", err)
+			continue
+		}
+
+		format, ok := detectDatasetFormat(sample)
+		if !ok {
+			invalid(`unknown format: expected a "text", "messages" or "prompt" and "completion" field`)
+			continue
+		}
+		if report.Format == "" {
+			report.Format = format
+		}
+		if format != report.Format {
+			invalid("sample is in %s format, but the dataset is in %s format", format, report.Format)
+			continue
+		}
+
+		text, err := datasetSampleText(format, sample)
+		if err != nil {
+			invalid("%v", err)
+			continue
+		}
+
+		tokens := estimateTextTokens(text)
+		if tokens > maxTokens {
+			invalid("sample has about %d tokens, more than the maximum of %d", tokens, maxTokens)
+			continue
+		}
+
+		report.Samples++
+		report.EstimatedTokens += tokens
+		report.MaxSampleTokens = max(report.MaxSampleTokens, tokens)
+	}
+
+	return report, nil
+}
+
+func detectDatasetFormat(sample map[string]json.RawMessage) (DatasetFormat, bool) {
+	if _, ok := sample["messages"]; ok {
+		return DatasetFormatConversation, true
+	}
+	_, prompt := sample["prompt"]
+	_, completion := sample["completion"]
+	if prompt || completion {
+		return DatasetFormatInstruction, true
+	}
+	if _, ok := sample["text"]; ok {
+		return DatasetFormatText, true
+	}
+	return "", false
+}
+
+// datasetSampleText validates a sample and returns its text, which is used to
+// estimate the number of tokens.
+func datasetSampleText(format DatasetFormat, sample map[string]json.RawMessage) (string, error) {
+	switch format {
+	case DatasetFormatText:
+		return datasetString(sample, "text")
+	case DatasetFormatInstruction:
+		prompt, err := datasetString(sample, "prompt")
+		if err != nil {
+			return "", err
+		}
+		completion, err := datasetString(sample, "completion")
+		if err != nil {
+			return "", err
+		}
+		return prompt + completion, nil
+	case DatasetFormatConversation:
+		var messages []struct {
+			Role    *string `json:"role"`
+			Content *string `json:"content"`
+		}
+		if err := json.Unmarshal(sample["messages"], &messages); err != nil {
+			return "", errors.New(`"messages" must be an array of objects with a string "role" and "content"`)
+		}
+		if len(messages) == 0 {
+			return "", errors.New(`"messages" is empty`)
+		}
+
+		var text strings.Builder
+		assistant := false
+		for i, message := range messages {
+			if message.Role == nil {
+				return "", fmt.Errorf("message %d has no role", i)
+			}
+			switch *message.Role {
+			case RoleSystem, RoleUser:
+			case RoleAssistant:
+				assistant = true
+			default:
+				return "", fmt.Errorf("message %d has invalid role %q", i, *message.Role)
+			}
+			if message.Content == nil || strings.TrimSpace(*message.Content) == "" {
+				return "", fmt.Errorf("message %d has empty content", i)
+			}
+			text.WriteString(*message.Content)
+		}
+		if !assistant {
+			return "", errors.New("conversation has no assistant message")
+		}
+		return text.String(), nil
+	}
+	return "", fmt.Errorf("unsupported format %q", format)
+}
+
+// datasetString returns the non-empty string field key of a sample.
+func datasetString(sample map[string]json.RawMessage, key string) (string, error) {
+	raw, ok := sample[key]
+	if !ok {
+		return "", fmt.Errorf("missing %q field", key)
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return "", fmt.Errorf("%q must be a string", key)
+	}
+	if strings.TrimSpace(s) == "" {
+		return "", fmt.Errorf("%q is empty", key)
+	}
+	return s, nil
+}
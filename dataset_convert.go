@@ -0,0 +1,293 @@
+package together
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+)
+
+// DatasetSample is a single fine-tuning sample. Exactly one of Text, Messages
+// or Prompt and Completion is set, matching the DatasetFormat of the dataset.
+type DatasetSample struct {
+	Text       string    `json:"text,omitempty"`
+	Messages   []Message `json:"messages,omitempty"`
+	Prompt     string    `json:"prompt,omitempty"`
+	Completion string    `json:"completion,omitempty"`
+}
+
+// Format returns the format of the sample.
+func (s DatasetSample) Format() DatasetFormat {
+	switch {
+	case len(s.Messages) > 0:
+		return DatasetFormatConversation
+	case s.Prompt != "" || s.Completion != "":
+		return DatasetFormatInstruction
+	}
+	return DatasetFormatText
+}
+
+type PromptCompletion struct {
+	Prompt     string
+	Completion string
+}
+
+// CSVColumns names the CSV columns that samples are built from. Set Text for
+// plain text samples, Prompt and Completion for instruction samples, or User
+// and Assistant for single-turn conversations.
+type CSVColumns struct {
+	Text       string
+	Prompt     string
+	Completion string
+	User       string
+	Assistant  string
+	System     string // Optional system prompt added to every conversation.
+}
+
+// ConversationSamples converts conversations into conversational samples.
+// Only the role and content of each message are kept.
+func ConversationSamples(conversations [][]Message) ([]DatasetSample, error) {
+	samples := make([]DatasetSample, len(conversations))
+	for i, conversation := range conversations {
+		if len(conversation) == 0 {
+			return nil, fmt.Errorf("conversation %d is empty", i)
+		}
+
+		messages := make([]Message, len(conversation))
+		for j, message := range conversation {
+			switch message.Role {
+			case RoleSystem, RoleUser, RoleAssistant:
+			default:
+				return nil, fmt.Errorf("conversation %d: message %d has unsupported role %q", i, j, message.Role)
+			}
+			messages[j] = Message{Role: message.Role, Content: message.Content}
+		}
+		samples[i] = DatasetSample{Messages: messages}
+	}
+	return samples, nil
+}
+
+// InstructionSamples converts prompt and completion pairs into instruction samples.
+func InstructionSamples(pairs []PromptCompletion) []DatasetSample {
+	samples := make([]DatasetSample, len(pairs))
+	for i, pair := range pairs {
+		samples[i] = DatasetSample{Prompt: pair.Prompt, Completion: pair.Completion}
+	}
+	return samples
+}
+
+// CSVSamples reads samples from CSV rows. The first row must be a header
+// naming the columns.
+func CSVSamples(r io.Reader, columns CSVColumns) ([]DatasetSample, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[name] = i
+	}
+	column := func(name string) (int, error) {
+		i, ok := index[name]
+		if !ok {
+			return 0, fmt.Errorf("CSV has no column %q", name)
+		}
+		return i, nil
+	}
+
+	var build func(row []string) DatasetSample
+	switch {
+	case columns.Text != "":
+		text, err := column(columns.Text)
+		if err != nil {
+			return nil, err
+		}
+		build = func(row []string) DatasetSample {
+			return DatasetSample{Text: row[text]}
+		}
+	case columns.Prompt != "" || columns.Completion != "":
+		prompt, err := column(columns.Prompt)
+		if err != nil {
+			return nil, err
+		}
+		completion, err := column(columns.Completion)
+		if err != nil {
+			return nil, err
+		}
+		build = func(row []string) DatasetSample {
+			return DatasetSample{Prompt: row[prompt], Completion: row[completion]}
+		}
+	case columns.User != "" || columns.Assistant != "":
+		user, err := column(columns.User)
+		if err != nil {
+			return nil, err
+		}
+		assistant, err := column(columns.Assistant)
+		if err != nil {
+			return nil, err
+		}
+		build = func(row []string) DatasetSample {
+			var messages []Message
+			if columns.System != "" {
+				messages = append(messages, Message{Role: RoleSystem, Content: columns.System})
+			}
+			messages = append(messages,
+				Message{Role: RoleUser, Content: row[user]},
+				Message{Role: RoleAssistant, Content: row[assistant]},
+			)
+			return DatasetSample{Messages: messages}
+		}
+	default:
+		return nil, errors.New("no CSV columns provided")
+	}
+
+	var samples []DatasetSample
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return samples, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, build(row))
+	}
+}
+
+type DatasetSplitOptions struct {
+	ValidationRatio   float64 // Fraction of samples held out for validation, in [0, 1).
+	Shuffle           bool    // Shuffle the samples before splitting.
+	Seed              int64   // Seed of the shuffle, so the same input always gives the same split.
+	Deduplicate       bool    // Drop samples identical to an earlier sample.
+	MaxSequenceTokens int     // Training sequence length; if set, a packing hint is computed.
+}
+
+// PackingHint estimates how well samples would pack into training sequences.
+// Packing is worthwhile when several samples fit in one sequence.
+type PackingHint struct {
+	AverageTokens      int // Estimated tokens of an average sample.
+	SamplesPerSequence int // Average number of samples that fit in one sequence.
+}
+
+type DatasetSplit struct {
+	Train      []DatasetSample
+	Validation []DatasetSample
+	Duplicates int          // Number of samples dropped as duplicates.
+	Packing    *PackingHint // Set if DatasetSplitOptions.MaxSequenceTokens is set.
+}
+
+// SplitDataset deduplicates, shuffles and splits samples into a training and
+// a validation set. The input slice is not modified.
+func SplitDataset(samples []DatasetSample, opts DatasetSplitOptions) (DatasetSplit, error) {
+	if opts.ValidationRatio < 0 || opts.ValidationRatio >= 1 {
+		return DatasetSplit{}, fmt.Errorf("invalid validation ratio %v: must be in [0, 1)", opts.ValidationRatio)
+	}
+	for i, sample := range samples {
+		if sample.Format() != samples[0].Format() {
+			return DatasetSplit{}, fmt.Errorf("sample %d is in %s format, but sample 0 is in %s format", i, sample.Format(), samples[0].Format())
+		}
+	}
+
+	var split DatasetSplit
+	kept := make([]DatasetSample, 0, len(samples))
+	seen := make(map[string]bool)
+	for _, sample := range samples {
+		if opts.Deduplicate {
+			key, err := json.Marshal(sample)
+			if err != nil {
+				return DatasetSplit{}, err
+			}
+			if seen[string(key)] {
+				split.Duplicates++
+				continue
+			}
+			seen[string(key)] = true
+		}
+		kept = append(kept, sample)
+	}
+
+	if opts.Shuffle {
+		rng := rand.New(rand.NewSource(opts.Seed))
+		rng.Shuffle(len(kept), func(i, j int) { kept[i], kept[j] = kept[j], kept[i] })
+	}
+
+	validation := int(math.Round(float64(len(kept)) * opts.ValidationRatio))
+	split.Train = kept[: len(kept)-validation : len(kept)-validation]
+	split.Validation = kept[len(kept)-validation:]
+
+	if opts.MaxSequenceTokens > 0 && len(kept) > 0 {
+		total := 0
+		for _, sample := range kept {
+			total += sampleTokens(sample)
+		}
+		average := max(total/len(kept), 1)
+		split.Packing = &PackingHint{
+			AverageTokens:      average,
+			SamplesPerSequence: max(opts.MaxSequenceTokens/average, 1),
+		}
+	}
+
+	return split, nil
+}
+
+// WriteDatasetJSONL writes samples to w as JSONL, one sample per line.
+func WriteDatasetJSONL(w io.Writer, samples []DatasetSample) error {
+	enc := json.NewEncoder(w)
+	for _, sample := range samples {
+		if err := enc.Encode(sample); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteDatasetFiles splits samples and writes the training and validation sets
+// to JSONL files ready for UploadFile. The validation file is only written if
+// the validation set is not empty.
+func WriteDatasetFiles(trainPath, validationPath string, samples []DatasetSample, opts DatasetSplitOptions) (DatasetSplit, error) {
+	if trainPath == "" {
+		return DatasetSplit{}, errors.New("no training file path provided")
+	}
+	if opts.ValidationRatio > 0 && (validationPath == "" || validationPath == trainPath) {
+		return DatasetSplit{}, errors.New("no separate validation file path provided")
+	}
+
+	split, err := SplitDataset(samples, opts)
+	if err != nil {
+		return DatasetSplit{}, err
+	}
+	if len(split.Train) == 0 {
+		return DatasetSplit{}, errors.New("training set is empty")
+	}
+
+	files := map[string][]DatasetSample{trainPath: split.Train}
+	if len(split.Validation) > 0 {
+		files[validationPath] = split.Validation
+	}
+	for path, samples := range files {
+		var buf bytes.Buffer
+		if err := WriteDatasetJSONL(&buf, samples); err != nil {
+			return DatasetSplit{}, err
+		}
+		if err := writeFileAtomic(path, buf.Bytes(), 0o644); err != nil {
+			return DatasetSplit{}, err
+		}
+	}
+
+	return split, nil
+}
+
+// sampleTokens estimates the number of tokens in a sample.
+func sampleTokens(sample DatasetSample) int {
+	chars := len(sample.Text) + len(sample.Prompt) + len(sample.Completion)
+	for _, message := range sample.Messages {
+		chars += len(message.Content)
+	}
+	return (chars + charsPerToken - 1) / charsPerToken
+}
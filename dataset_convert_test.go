@@ -0,0 +1,145 @@
+package together
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDatasetConverters(t *testing.T) {
+	// Case: Conversations keep only roles and content
+	samples, err := ConversationSamples([][]Message{{
+		{Role: RoleUser, Content: "hi", Name: "me"},
+		{Role: RoleAssistant, Content: "hello", ContentParts: []ContentPart{TextPart("hello")}},
+	}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []DatasetSample{{Messages: []Message{{Role: RoleUser, Content: "hi"}, {Role: RoleAssistant, Content: "hello"}}}}
+	if !reflect.DeepEqual(samples, want) {
+		t.Errorf("Result was incorrect, got: %+v, want: %+v.", samples, want)
+	}
+
+	// Case: Conversations with tool messages are rejected
+	if _, err := ConversationSamples([][]Message{{{Role: RoleTool, Content: "42"}}}); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, `conversation 0: message 0 has unsupported role "tool"`)
+	}
+
+	// Case: Prompt and completion pairs
+	samples = InstructionSamples([]PromptCompletion{{Prompt: "2+2=", Completion: "4"}})
+	if !reflect.DeepEqual(samples, []DatasetSample{{Prompt: "2+2=", Completion: "4"}}) || samples[0].Format() != DatasetFormatInstruction {
+		t.Errorf("Result was incorrect, got: %+v.", samples)
+	}
+
+	// Case: CSV rows as conversations
+	csv := "question,answer\n\"hi, there\",hello\nbye,goodbye\n"
+	samples, err = CSVSamples(strings.NewReader(csv), CSVColumns{User: "question", Assistant: "answer", System: "be nice"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(samples) != 2 || len(samples[0].Messages) != 3 || samples[0].Messages[1].Content != "hi, there" || samples[1].Messages[2].Content != "goodbye" {
+		t.Errorf("Result was incorrect, got: %+v.", samples)
+	}
+
+	// Case: CSV rows as text and instructions
+	samples, _ = CSVSamples(strings.NewReader(csv), CSVColumns{Text: "answer"})
+	if !reflect.DeepEqual(samples, []DatasetSample{{Text: "hello"}, {Text: "goodbye"}}) {
+		t.Errorf("Result was incorrect, got: %+v.", samples)
+	}
+	samples, _ = CSVSamples(strings.NewReader(csv), CSVColumns{Prompt: "question", Completion: "answer"})
+	if !reflect.DeepEqual(samples[1], DatasetSample{Prompt: "bye", Completion: "goodbye"}) {
+		t.Errorf("Result was incorrect, got: %+v.", samples)
+	}
+
+	// Case: Missing CSV columns
+	if _, err := CSVSamples(strings.NewReader(csv), CSVColumns{Text: "body"}); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, `CSV has no column "body"`)
+	}
+	if _, err := CSVSamples(strings.NewReader(csv), CSVColumns{}); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no CSV columns provided")
+	}
+}
+
+func TestSplitDataset(t *testing.T) {
+	var samples []DatasetSample
+	for _, text := range strings.Split("a b c d e f g h i j a b", " ") {
+		samples = append(samples, DatasetSample{Text: strings.Repeat(text, 8)})
+	}
+
+	// Case: Invalid ratios and mixed formats are rejected
+	if _, err := SplitDataset(samples, DatasetSplitOptions{ValidationRatio: 1}); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "invalid validation ratio")
+	}
+	if _, err := SplitDataset([]DatasetSample{{Text: "a"}, {Prompt: "b", Completion: "c"}}, DatasetSplitOptions{}); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "sample 1 is in instruction format")
+	}
+
+	// Case: Deduplicated, shuffled and split deterministically
+	opts := DatasetSplitOptions{ValidationRatio: 0.2, Shuffle: true, Seed: 42, Deduplicate: true, MaxSequenceTokens: 16}
+	split, err := SplitDataset(samples, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(split.Train) != 8 || len(split.Validation) != 2 || split.Duplicates != 2 {
+		t.Errorf("Result was incorrect, got: %d train, %d validation, %d duplicates.", len(split.Train), len(split.Validation), split.Duplicates)
+	}
+	if !reflect.DeepEqual(split.Packing, &PackingHint{AverageTokens: 2, SamplesPerSequence: 8}) {
+		t.Errorf("Result was incorrect, got: %+v.", split.Packing)
+	}
+	again, _ := SplitDataset(samples, opts)
+	if !reflect.DeepEqual(split, again) {
+		t.Errorf("Result was incorrect, got: %+v, want: %+v.", again, split)
+	}
+	if samples[0].Text != "aaaaaaaa" {
+		t.Errorf("Result was incorrect, input was modified: %+v.", samples)
+	}
+
+	// Case: Without options the samples are kept in order
+	split, _ = SplitDataset(samples, DatasetSplitOptions{})
+	if !reflect.DeepEqual(split.Train, samples) || len(split.Validation) != 0 || split.Packing != nil {
+		t.Errorf("Result was incorrect, got: %+v.", split)
+	}
+}
+
+func TestWriteDatasetFiles(t *testing.T) {
+	dir := t.TempDir()
+	train, validation := filepath.Join(dir, "train.jsonl"), filepath.Join(dir, "validation.jsonl")
+	samples, _ := ConversationSamples([][]Message{
+		{{Role: RoleUser, Content: "1"}, {Role: RoleAssistant, Content: "one"}},
+		{{Role: RoleUser, Content: "2"}, {Role: RoleAssistant, Content: "two"}},
+		{{Role: RoleUser, Content: "3"}, {Role: RoleAssistant, Content: "three"}},
+		{{Role: RoleUser, Content: "4"}, {Role: RoleAssistant, Content: "four"}},
+	})
+
+	// Case: A validation path is required when splitting
+	if _, err := WriteDatasetFiles(train, "", samples, DatasetSplitOptions{ValidationRatio: 0.25}); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no separate validation file path provided")
+	}
+
+	// Case: Written files are valid datasets
+	split, err := WriteDatasetFiles(train, validation, samples, DatasetSplitOptions{ValidationRatio: 0.25})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for path, want := range map[string]int{train: len(split.Train), validation: len(split.Validation)} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if info, _ := os.Stat(path); info.Mode().Perm() != 0o644 {
+			t.Errorf("Result was incorrect for %s, got: %v, want: %v.", path, info.Mode().Perm(), os.FileMode(0o644))
+		}
+		report, _ := ValidateDataset(bytes.NewReader(data))
+		if !report.Valid() || report.Samples != want || report.Format != DatasetFormatConversation {
+			t.Errorf("Result was incorrect for %s, got: %+v, want: %d samples.", path, report, want)
+		}
+	}
+
+	data, _ := os.ReadFile(validation)
+	if string(data) != `{"messages":[{"role":"user","content":"4"},{"role":"assistant","content":"four"}]}`+"\n" {
+		t.Errorf("Result was incorrect, got: %s.", data)
+	}
+}
@@ -0,0 +1,94 @@
+package together
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestValidateDataset(t *testing.T) {
+	// Case: Valid datasets of each format
+	datasets := map[DatasetFormat]string{
+		DatasetFormatText:         `{"text":"hello world"}` + "\n" + `{"text":"abcd"}`,
+		DatasetFormatInstruction:  `{"prompt":"2+2=","completion":"4"}` + "\n" + `{"prompt":"1+1=","completion":"2"}` + "\n",
+		DatasetFormatConversation: `{"messages":[{"role":"system","content":"be brief"},{"role":"user","content":"hi"},{"role":"assistant","content":"hello"}]}` + "\n",
+	}
+	for format, dataset := range datasets {
+		report, err := ValidateDataset(strings.NewReader(dataset))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if report.Format != format || !report.Valid() || report.Err() != nil {
+			t.Errorf("Result was incorrect, got: %+v, want: valid %s dataset.", report, format)
+		}
+	}
+
+	// Case: Statistics
+	report, _ := ValidateDataset(strings.NewReader(datasets[DatasetFormatText]))
+	want := DatasetReport{Format: DatasetFormatText, Lines: 2, Samples: 2, EstimatedTokens: 4, MaxSampleTokens: 3}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("Result was incorrect, got: %+v, want: %+v.", report, want)
+	}
+
+	// Case: Invalid lines are reported with line numbers
+	dataset := strings.Join([]string{
+		`{"messages":[{"role":"user","content":"hi"},{"role":"assistant","content":"hello"}]}`,
+		``,
+		`{"messages":`,
+		`{"messages":[{"role":"robot","content":"beep"}]}`,
+		`{"messages":[{"role":"user","content":"hi"},{"role":"assistant","content":" "}]}`,
+		`{"messages":[{"role":"user","content":"hi"}]}`,
+		`{"messages":[]}`,
+		`{"prompt":"a","completion":"b"}`,
+		`{"foo":"bar"}`,
+		`{"messages":[{"role":"user","content":"` + strings.Repeat("a", 100) + `"},{"role":"assistant","content":"b"}]}`,
+	}, "\n")
+	report, err := DatasetValidator{MaxSampleTokens: 20}.Validate(strings.NewReader(dataset))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	wantErrors := []DatasetLineError{
+		{Line: 2, Message: "empty line"},
+		{Line: 3, Message: "invalid JSON: unexpected end of JSON input"},
+		{Line: 4, Message: `message 0 has invalid role "robot"`},
+		{Line: 5, Message: "message 1 has empty content"},
+		{Line: 6, Message: "conversation has no assistant message"},
+		{Line: 7, Message: `"messages" is empty`},
+		{Line: 8, Message: "sample is in instruction format, but the dataset is in conversation format"},
+		{Line: 9, Message: `unknown format: expected a "text", "messages" or "prompt" and "completion" field`},
+		{Line: 10, Message: "sample has about 26 tokens, more than the maximum of 20"},
+	}
+	if !reflect.DeepEqual(report.Errors, wantErrors) {
+		t.Errorf("Result was incorrect, got: %+v, want: %+v.", report.Errors, wantErrors)
+	}
+	if report.Lines != 10 || report.Samples != 1 || report.Valid() {
+		t.Errorf("Result was incorrect, got: %+v.", report)
+	}
+	if err := report.Err(); err == nil || !strings.HasPrefix(err.Error(), "line 2: empty line\nline 3:") {
+		t.Errorf("Error was incorrect, got: %v.", err)
+	}
+
+	// Case: Missing instruction fields
+	report, _ = DatasetValidator{Format: DatasetFormatInstruction}.Validate(strings.NewReader(`{"prompt":"a"}` + "\n" + `{"prompt":"a","completion":1}`))
+	wantErrors = []DatasetLineError{
+		{Line: 1, Message: `missing "completion" field`},
+		{Line: 2, Message: `"completion" must be a string`},
+	}
+	if !reflect.DeepEqual(report.Errors, wantErrors) {
+		t.Errorf("Result was incorrect, got: %+v, want: %+v.", report.Errors, wantErrors)
+	}
+
+	// Case: Empty datasets are invalid
+	report, _ = ValidateDataset(strings.NewReader(""))
+	if report.Valid() || report.Err() == nil {
+		t.Errorf("Result was incorrect, got: %+v, want: %s.", report, "dataset contains no samples")
+	}
+
+	// Case: Read errors are returned
+	readErr := errors.New("read failed")
+	if _, err := ValidateDataset(iotest.ErrReader(readErr)); !errors.Is(err, readErr) {
+		t.Errorf("Error was incorrect, got: %v, want: %v.", err, readErr)
+	}
+}
@@ -7,39 +7,65 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 )
 
 type EmbeddingsRequest struct {
-	Model string `json:"model"`
-	Input string `json:"input"`
+	Model string   `json:"model"`
+	Input []string `json:"input"`
 }
 
 type EmbeddingsResponse struct {
-	Status     string   `json:"status"`
-	Prompt     []string `json:"prompt"`
-	Model      string   `json:"model"`
-	ModelOwner string   `json:"model_owner"`
-	Tags       Tags     `json:"tags"`
-	NumReturns int      `json:"num_returns"`
-	Args       Args     `json:"args"`
-	Subjobs    []Subjob `json:"subjobs"`
-	Output     Output   `json:"output"`
+	Object string            `json:"object"`
+	Data   []EmbeddingObject `json:"data"`
+	Model  string            `json:"model"`
+	Usage  UsageObject       `json:"usage"`
 }
 
-// Embeddings is the endpoint for embedding models on Together AI.
+type EmbeddingObject struct {
+	Object    string    `json:"object"`
+	Index     int       `json:"index"` // Position of the corresponding input.
+	Embedding []float32 `json:"embedding"`
+}
+
+// Vectors returns the embeddings in input order.
+func (r EmbeddingsResponse) Vectors() [][]float32 {
+	vectors := make([][]float32, len(r.Data))
+	for i, data := range r.Data {
+		vectors[i] = data.Embedding
+	}
+	return vectors
+}
+
+// Embeddings is the endpoint for embedding models on Together AI. Multiple
+// inputs are embedded in a single request and returned in input order. If the
+// client has an embedding cache, only inputs missing from it are sent.
 //
 // API Reference: https://docs.together.ai/reference/embeddings
-func (api *API) Embeddings(ctx context.Context, model string, input string, request EmbeddingsRequest) (EmbeddingsResponse, error) {
+func (api *API) Embeddings(ctx context.Context, model string, input []string, request EmbeddingsRequest) (EmbeddingsResponse, error) {
 	if ctx == nil {
 		return EmbeddingsResponse{}, fmt.Errorf("no context provided")
 	}
 	if model == "" {
 		return EmbeddingsResponse{}, fmt.Errorf("no model provided")
 	}
-	if input == "" {
+	if len(input) == 0 {
 		return EmbeddingsResponse{}, fmt.Errorf("no input provided")
 	}
+	for i, s := range input {
+		if s == "" {
+			return EmbeddingsResponse{}, fmt.Errorf("input %d is empty", i)
+		}
+	}
 
+	if api.embeddingCache != nil {
+		return api.cachedEmbeddings(ctx, model, input, request)
+	}
+	return api.embeddings(ctx, model, input, request)
+}
+
+// embeddings sends a validated embeddings request to the endpoint.
+func (api *API) embeddings(ctx context.Context, model string, input []string, request EmbeddingsRequest) (EmbeddingsResponse, error) {
 	request.Model = model
 	request.Input = input
 
@@ -60,7 +86,7 @@ func (api *API) Embeddings(ctx context.Context, model string, input string, requ
 		return EmbeddingsResponse{}, err
 	}
 	if res.StatusCode != http.StatusOK {
-		return EmbeddingsResponse{}, fmt.Errorf("HTTP request failed: %s", string(body))
+		return EmbeddingsResponse{}, newAPIError(res, body)
 	}
 
 	var embeddingsResponse EmbeddingsResponse
@@ -69,5 +95,17 @@ func (api *API) Embeddings(ctx context.Context, model string, input string, requ
 		return EmbeddingsResponse{}, err
 	}
 
+	if len(embeddingsResponse.Data) != len(input) {
+		return EmbeddingsResponse{}, fmt.Errorf("expected %d embeddings, got %d", len(input), len(embeddingsResponse.Data))
+	}
+	sort.SliceStable(embeddingsResponse.Data, func(i, j int) bool {
+		return embeddingsResponse.Data[i].Index < embeddingsResponse.Data[j].Index
+	})
+	for i, data := range embeddingsResponse.Data {
+		if data.Index != i {
+			return EmbeddingsResponse{}, fmt.Errorf("missing embedding for input %d", i)
+		}
+	}
+
 	return embeddingsResponse, nil
 }
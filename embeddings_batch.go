@@ -0,0 +1,195 @@
+package together
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultEmbedBatchSize   = 64
+	defaultEmbedConcurrency = 4
+	embedRetryWait          = 500 * time.Millisecond
+)
+
+type EmbedAllOptions struct {
+	Request        EmbeddingsRequest     // Base request sent for every batch.
+	BatchSize      int                   // Maximum inputs per request; defaults to 64.
+	MaxBatchTokens int                   // Maximum estimated tokens per request; zero means no limit.
+	Concurrency    int                   // Number of concurrent requests; defaults to 4.
+	Retries        int                   // Retries of a failed batch, on top of the client's HTTP retries.
+	Progress       func(done, total int) // Called after each batch with the number of processed inputs.
+}
+
+type EmbedAllResult struct {
+	Vectors [][]float32   // Embeddings in input order; nil for inputs that failed.
+	Errors  map[int]error // Errors keyed by input index.
+	Usage   UsageObject   // Usage aggregated across all batches.
+}
+
+type embedBatch struct {
+	indices []int
+	inputs  []string
+}
+
+// EmbedAll embeds a large number of inputs by splitting them into batches that
+// are sent concurrently. Results are returned in input order.
+//
+// If any input fails, the partial result is returned together with an error,
+// and the failed inputs are listed in EmbedAllResult.Errors.
+func (api *API) EmbedAll(ctx context.Context, model string, inputs []string, opts EmbedAllOptions) (EmbedAllResult, error) {
+	if ctx == nil {
+		return EmbedAllResult{}, fmt.Errorf("no context provided")
+	}
+	if model == "" {
+		return EmbedAllResult{}, fmt.Errorf("no model provided")
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultEmbedConcurrency
+	}
+
+	result := EmbedAllResult{
+		Vectors: make([][]float32, len(inputs)),
+		Errors:  make(map[int]error),
+	}
+
+	for i, input := range inputs {
+		if input == "" {
+			result.Errors[i] = fmt.Errorf("input %d is empty", i)
+		}
+	}
+
+	batches := splitEmbedBatches(inputs, opts.BatchSize, opts.MaxBatchTokens)
+	queue := make(chan embedBatch, len(batches))
+	for _, batch := range batches {
+		queue <- batch
+	}
+	close(queue)
+
+	var mu sync.Mutex
+	done := len(result.Errors)
+
+	var wg sync.WaitGroup
+	for i := 0; i < min(concurrency, len(batches)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range queue {
+				resp, err := api.embedBatch(ctx, model, batch, opts)
+
+				mu.Lock()
+				if err != nil {
+					for _, index := range batch.indices {
+						result.Errors[index] = err
+					}
+				} else {
+					for i, vector := range resp.Vectors() {
+						result.Vectors[batch.indices[i]] = vector
+					}
+					result.Usage.PromptTokens += resp.Usage.PromptTokens
+					result.Usage.CompletionTokens += resp.Usage.CompletionTokens
+					result.Usage.TotalTokens += resp.Usage.TotalTokens
+				}
+				done += len(batch.indices)
+				if opts.Progress != nil {
+					opts.Progress(done, len(inputs))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(result.Errors) > 0 {
+		first := len(inputs)
+		for index := range result.Errors {
+			first = min(first, index)
+		}
+		return result, fmt.Errorf("%d of %d inputs failed to embed: %w", len(result.Errors), len(inputs), result.Errors[first])
+	}
+
+	return result, nil
+}
+
+// embedBatch embeds a single batch, retrying failures that may be transient.
+func (api *API) embedBatch(ctx context.Context, model string, batch embedBatch, opts EmbedAllOptions) (EmbeddingsResponse, error) {
+	var resp EmbeddingsResponse
+	var err error
+
+	for attempt := 0; attempt <= opts.Retries; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(embedRetryWait * time.Duration(attempt))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return EmbeddingsResponse{}, ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return EmbeddingsResponse{}, ctxErr
+		}
+
+		resp, err = api.Embeddings(ctx, model, batch.inputs, opts.Request)
+		if err == nil || !retryableBatchError(err) {
+			return resp, err
+		}
+	}
+
+	return resp, err
+}
+
+// retryableBatchError reports whether a failed batch is worth retrying. Client
+// errors other than rate limiting will fail the same way again.
+func retryableBatchError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	return true
+}
+
+// splitEmbedBatches groups the non-empty inputs into batches bounded by size
+// and estimated token count. An input exceeding the token budget on its own is
+// sent in a batch by itself.
+func splitEmbedBatches(inputs []string, batchSize, maxTokens int) []embedBatch {
+	if batchSize <= 0 {
+		batchSize = defaultEmbedBatchSize
+	}
+
+	var batches []embedBatch
+	var current embedBatch
+	tokens := 0
+
+	for i, input := range inputs {
+		if input == "" {
+			continue
+		}
+
+		inputTokens := estimateTextTokens(input)
+		if len(current.indices) > 0 && (len(current.indices) >= batchSize || (maxTokens > 0 && tokens+inputTokens > maxTokens)) {
+			batches = append(batches, current)
+			current = embedBatch{}
+			tokens = 0
+		}
+
+		current.indices = append(current.indices, i)
+		current.inputs = append(current.inputs, input)
+		tokens += inputTokens
+	}
+
+	if len(current.indices) > 0 {
+		batches = append(batches, current)
+	}
+	return batches
+}
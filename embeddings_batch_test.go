@@ -0,0 +1,103 @@
+package together
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSplitEmbedBatches(t *testing.T) {
+	inputs := []string{"aaaa", "", "bbbb", "cccccccccccc", "dddd"}
+
+	// Case: Bounded by size, skipping empty inputs
+	batches := splitEmbedBatches(inputs, 2, 0)
+	want := [][]int{{0, 2}, {3, 4}}
+	if len(batches) != len(want) {
+		t.Fatalf("Result was incorrect, got: %d batches, want: %d.", len(batches), len(want))
+	}
+	for i, batch := range batches {
+		if !reflect.DeepEqual(batch.indices, want[i]) {
+			t.Errorf("Result was incorrect, got: %v, want: %v.", batch.indices, want[i])
+		}
+	}
+
+	// Case: Bounded by estimated tokens, oversized inputs are sent alone
+	batches = splitEmbedBatches(inputs, 0, 2)
+	want = [][]int{{0, 2}, {3}, {4}}
+	if len(batches) != len(want) {
+		t.Fatalf("Result was incorrect, got: %d batches, want: %d.", len(batches), len(want))
+	}
+	for i, batch := range batches {
+		if !reflect.DeepEqual(batch.indices, want[i]) {
+			t.Errorf("Result was incorrect, got: %v, want: %v.", batch.indices, want[i])
+		}
+	}
+}
+
+func TestEmbedAll(t *testing.T) {
+	// The server embeds each input as its length and rejects batches containing "bad".
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		var body EmbeddingsRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		resp := EmbeddingsResponse{Object: "list", Usage: UsageObject{TotalTokens: len(body.Input)}}
+		for i, input := range body.Input {
+			if input == "bad" {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprintln(w, `{"error":{"message":"bad input"}}`)
+				return
+			}
+			resp.Data = append(resp.Data, EmbeddingObject{Index: i, Embedding: []float32{float32(len(input))}})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer ts.Close()
+
+	req, _ := New("hunter2", WithBaseURL(ts.URL), WithRetryMax(0))
+
+	// Case: All inputs succeed and are returned in order
+	var progress []int
+	inputs := []string{"a", "bb", "ccc", "dddd", "eeeee"}
+	result, err := req.EmbedAll(context.TODO(), "m", inputs, EmbedAllOptions{
+		BatchSize:   2,
+		Concurrency: 1,
+		Progress:    func(done, total int) { progress = append(progress, done) },
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result.Vectors, [][]float32{{1}, {2}, {3}, {4}, {5}}) {
+		t.Errorf("Result was incorrect, got: %v.", result.Vectors)
+	}
+	if !reflect.DeepEqual(progress, []int{2, 4, 5}) {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", progress, []int{2, 4, 5})
+	}
+	if result.Usage.TotalTokens != 5 || atomic.LoadInt32(&requests) != 3 {
+		t.Errorf("Result was incorrect, got: %d tokens and %d requests.", result.Usage.TotalTokens, requests)
+	}
+
+	// Case: Failed batches are reported per input alongside partial results
+	atomic.StoreInt32(&requests, 0)
+	inputs = []string{"a", "bad", "", "dddd"}
+	result, err = req.EmbedAll(context.TODO(), "m", inputs, EmbedAllOptions{BatchSize: 2, Retries: 2})
+	if err == nil {
+		t.Fatalf("Error was incorrect, got: %v, want: %s.", err, "3 of 4 inputs failed to embed")
+	}
+	if len(result.Errors) != 3 || result.Errors[0] == nil || result.Errors[1] == nil || result.Errors[2] == nil {
+		t.Errorf("Result was incorrect, got: %v.", result.Errors)
+	}
+	if !reflect.DeepEqual(result.Vectors, [][]float32{nil, nil, nil, {4}}) {
+		t.Errorf("Result was incorrect, got: %v.", result.Vectors)
+	}
+	if atomic.LoadInt32(&requests) != 2 {
+		t.Errorf("Result was incorrect, got: %d requests, want: %d.", requests, 2)
+	}
+}
@@ -0,0 +1,299 @@
+package together
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const embeddingCacheExt = ".vec"
+
+// EmbeddingCache stores embeddings so that repeated inputs are not sent to the
+// endpoint again. Keys are the hex encoded SHA-256 of the input text, and
+// vectors are namespaced by model. Implementations must be safe for
+// concurrent use.
+type EmbeddingCache interface {
+	Get(model, key string) ([]float32, bool)
+	Set(model, key string, vector []float32) error
+}
+
+// WithEmbeddingCache enables caching of embeddings returned by Embeddings and
+// EmbedAll.
+func WithEmbeddingCache(cache EmbeddingCache) Option {
+	return func(api *API) error {
+		if cache == nil {
+			return errors.New("invalid embedding cache: must not be nil")
+		}
+
+		api.embeddingCache = cache
+		return nil
+	}
+}
+
+// EmbeddingCacheKey returns the cache key of an input.
+func EmbeddingCacheKey(input string) string {
+	sum := sha256.Sum256([]byte(input))
+	return hex.EncodeToString(sum[:])
+}
+
+// cachedEmbeddings serves inputs from the embedding cache and embeds the
+// misses in a single request. Duplicate inputs are only sent once.
+func (api *API) cachedEmbeddings(ctx context.Context, model string, input []string, request EmbeddingsRequest) (EmbeddingsResponse, error) {
+	keys := make([]string, len(input))
+	vectors := make([][]float32, len(input))
+	missIndex := make(map[string]int)
+	var misses []string
+
+	for i, s := range input {
+		keys[i] = EmbeddingCacheKey(s)
+		if vector, ok := api.embeddingCache.Get(model, keys[i]); ok {
+			vectors[i] = vector
+			continue
+		}
+		if _, ok := missIndex[keys[i]]; !ok {
+			missIndex[keys[i]] = len(misses)
+			misses = append(misses, s)
+		}
+	}
+
+	response := EmbeddingsResponse{Object: "list", Model: model}
+	if len(misses) > 0 {
+		resp, err := api.embeddings(ctx, model, misses, request)
+		if err != nil {
+			return EmbeddingsResponse{}, err
+		}
+		response.Object = resp.Object
+		response.Model = resp.Model
+		response.Usage = resp.Usage
+
+		fetched := resp.Vectors()
+		for i, s := range misses {
+			// A failing cache should not fail a request that succeeded.
+			if err := api.embeddingCache.Set(model, EmbeddingCacheKey(s), fetched[i]); err != nil {
+				api.logf("[WARN] embedding cache: %v", err)
+			}
+		}
+		for i, key := range keys {
+			if vectors[i] == nil {
+				vectors[i] = fetched[missIndex[key]]
+			}
+		}
+	}
+
+	response.Data = make([]EmbeddingObject, len(input))
+	for i, vector := range vectors {
+		response.Data[i] = EmbeddingObject{Object: "embedding", Index: i, Embedding: vector}
+	}
+	return response, nil
+}
+
+// FileEmbeddingCache is an EmbeddingCache that stores each vector in its own
+// file below a directory, with one subdirectory per model. When the total size
+// exceeds the configured limit, the least recently used vectors are evicted.
+//
+// The cache is safe for concurrent use within a process, but a directory must
+// not be shared by several processes at the same time.
+type FileEmbeddingCache struct {
+	dir      string
+	maxBytes int64
+
+	mu      sync.Mutex
+	entries map[string]*fileCacheEntry // Keyed by file path.
+	size    int64
+}
+
+type fileCacheEntry struct {
+	size int64
+	used time.Time
+}
+
+// NewFileEmbeddingCache opens or creates a cache in dir that holds at most
+// maxBytes of vectors. A maxBytes of zero means the cache is unbounded.
+func NewFileEmbeddingCache(dir string, maxBytes int64) (*FileEmbeddingCache, error) {
+	if dir == "" {
+		return nil, errors.New("no cache directory provided")
+	}
+	if maxBytes < 0 {
+		return nil, errors.New("invalid cache size: must not be negative")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	c := &FileEmbeddingCache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		entries:  make(map[string]*fileCacheEntry),
+	}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != embeddingCacheExt {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		c.entries[path] = &fileCacheEntry{size: info.Size(), used: info.ModTime()}
+		c.size += info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evict()
+	return c, nil
+}
+
+// Size returns the total size in bytes of the cached vectors.
+func (c *FileEmbeddingCache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// Get returns the vector stored for key. Unreadable entries are treated as misses.
+func (c *FileEmbeddingCache) Get(model, key string) ([]float32, bool) {
+	path, err := c.path(model, key)
+	if err != nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[path]
+	if !ok {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || len(data)%4 != 0 {
+		c.remove(path)
+		return nil, false
+	}
+
+	// The modification time records the last use so that eviction order
+	// survives reopening the cache.
+	entry.used = time.Now()
+	os.Chtimes(path, entry.used, entry.used)
+
+	vector := make([]float32, len(data)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+	}
+	return vector, true
+}
+
+// Set stores the vector for key, evicting old vectors if the cache is full.
+func (c *FileEmbeddingCache) Set(model, key string, vector []float32) error {
+	path, err := c.path(model, key)
+	if err != nil {
+		return err
+	}
+
+	data := make([]byte, 4*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(data[4*i:], math.Float32bits(v))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := writeFileAtomic(path, data, 0o600); err != nil {
+		return err
+	}
+
+	if entry, ok := c.entries[path]; ok {
+		c.size -= entry.size
+	}
+	c.entries[path] = &fileCacheEntry{size: int64(len(data)), used: time.Now()}
+	c.size += int64(len(data))
+	c.evict()
+	return nil
+}
+
+// path returns the file of a key. Models are hashed so that any model name
+// maps to a valid directory name.
+func (c *FileEmbeddingCache) path(model, key string) (string, error) {
+	if len(key) != sha256.Size*2 || strings.Trim(key, "0123456789abcdef") != "" {
+		return "", fmt.Errorf("invalid embedding cache key %q", key)
+	}
+
+	namespace := sha256.Sum256([]byte(model))
+	return filepath.Join(c.dir, hex.EncodeToString(namespace[:8]), key[:2], key+embeddingCacheExt), nil
+}
+
+// evict removes the least recently used vectors until the cache fits its limit.
+// The caller must hold c.mu.
+func (c *FileEmbeddingCache) evict() {
+	if c.maxBytes == 0 || c.size <= c.maxBytes {
+		return
+	}
+
+	paths := make([]string, 0, len(c.entries))
+	for path := range c.entries {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		return c.entries[paths[i]].used.Before(c.entries[paths[j]].used)
+	})
+
+	for _, path := range paths {
+		if c.size <= c.maxBytes {
+			break
+		}
+		c.remove(path)
+	}
+}
+
+// remove deletes a vector from disk and the index. The caller must hold c.mu.
+func (c *FileEmbeddingCache) remove(path string) {
+	if entry, ok := c.entries[path]; ok {
+		c.size -= entry.size
+		delete(c.entries, path)
+	}
+	os.Remove(path)
+}
+
+// writeFileAtomic writes data to a temporary file and renames it to path, so
+// that readers never observe a partially written file. The file is given the
+// permissions perm.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(perm); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,161 @@
+package together
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFileEmbeddingCache(t *testing.T) {
+	dir := t.TempDir()
+
+	// Case: Invalid configuration
+	if _, err := NewFileEmbeddingCache("", 0); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no cache directory provided")
+	}
+	if _, err := NewFileEmbeddingCache(dir, -1); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "invalid cache size: must not be negative")
+	}
+
+	// Each vector of two float32 values takes 8 bytes, so three fit.
+	cache, err := NewFileEmbeddingCache(dir, 24)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	a, b, c, d := EmbeddingCacheKey("a"), EmbeddingCacheKey("b"), EmbeddingCacheKey("c"), EmbeddingCacheKey("d")
+
+	// Case: Invalid keys are rejected
+	if err := cache.Set("m", "../escape", []float32{1}); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "invalid embedding cache key")
+	}
+
+	// Case: Round trip, namespaced by model
+	cache.Set("org/model", a, []float32{1, 2})
+	if vector, ok := cache.Get("org/model", a); !ok || !reflect.DeepEqual(vector, []float32{1, 2}) {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", vector, []float32{1, 2})
+	}
+	if _, ok := cache.Get("other/model", a); ok {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", ok, false)
+	}
+
+	// Case: The least recently used vector is evicted
+	cache.Set("org/model", b, []float32{3, 4})
+	time.Sleep(10 * time.Millisecond)
+	cache.Set("org/model", c, []float32{5, 6})
+	time.Sleep(10 * time.Millisecond)
+	cache.Get("org/model", a)
+	time.Sleep(10 * time.Millisecond)
+	cache.Set("org/model", d, []float32{7, 8})
+
+	if _, ok := cache.Get("org/model", b); ok {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", ok, false)
+	}
+	for _, key := range []string{a, c, d} {
+		if _, ok := cache.Get("org/model", key); !ok {
+			t.Errorf("Result was incorrect, got: %v, want: %v.", ok, true)
+		}
+	}
+	if cache.Size() != 24 {
+		t.Errorf("Result was incorrect, got: %d, want: %d.", cache.Size(), 24)
+	}
+
+	// Case: Reopening the cache keeps the stored vectors
+	reopened, err := NewFileEmbeddingCache(dir, 24)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if reopened.Size() != 24 {
+		t.Errorf("Result was incorrect, got: %d, want: %d.", reopened.Size(), 24)
+	}
+	if vector, ok := reopened.Get("org/model", d); !ok || !reflect.DeepEqual(vector, []float32{7, 8}) {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", vector, []float32{7, 8})
+	}
+
+	// Case: Reopening with a smaller limit evicts
+	reopened, _ = NewFileEmbeddingCache(dir, 8)
+	if reopened.Size() != 8 {
+		t.Errorf("Result was incorrect, got: %d, want: %d.", reopened.Size(), 8)
+	}
+}
+
+func TestEmbeddingsCache(t *testing.T) {
+	// The server embeds each input as its length and records what it was sent.
+	var mu sync.Mutex
+	var sent [][]string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body EmbeddingsRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		mu.Lock()
+		sent = append(sent, body.Input)
+		mu.Unlock()
+
+		resp := EmbeddingsResponse{Object: "list", Model: body.Model, Usage: UsageObject{TotalTokens: len(body.Input)}}
+		for i, input := range body.Input {
+			resp.Data = append(resp.Data, EmbeddingObject{Object: "embedding", Index: i, Embedding: []float32{float32(len(input))}})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer ts.Close()
+
+	// Case: Nil caches are rejected
+	if _, err := New("hunter2", WithEmbeddingCache(nil)); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "invalid embedding cache: must not be nil")
+	}
+
+	cache, _ := NewFileEmbeddingCache(t.TempDir(), 0)
+	req, _ := New("hunter2", WithBaseURL(ts.URL), WithRetryMax(0), WithEmbeddingCache(cache))
+
+	// Case: Duplicate misses are sent once
+	resp, err := req.Embeddings(context.TODO(), "m", []string{"a", "bb", "a"}, EmbeddingsRequest{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(resp.Vectors(), [][]float32{{1}, {2}, {1}}) {
+		t.Errorf("Result was incorrect, got: %v.", resp.Vectors())
+	}
+	if !reflect.DeepEqual(sent, [][]string{{"a", "bb"}}) {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", sent, [][]string{{"a", "bb"}})
+	}
+
+	// Case: Only misses are sent, results stay in input order
+	resp, err = req.Embeddings(context.TODO(), "m", []string{"ccc", "a", "dddd"}, EmbeddingsRequest{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(resp.Vectors(), [][]float32{{3}, {1}, {4}}) {
+		t.Errorf("Result was incorrect, got: %v.", resp.Vectors())
+	}
+	if !reflect.DeepEqual(sent[1], []string{"ccc", "dddd"}) {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", sent[1], []string{"ccc", "dddd"})
+	}
+	if resp.Usage.TotalTokens != 2 {
+		t.Errorf("Result was incorrect, got: %d, want: %d.", resp.Usage.TotalTokens, 2)
+	}
+
+	// Case: Fully cached inputs make no request
+	resp, err = req.Embeddings(context.TODO(), "m", []string{"bb", "ccc"}, EmbeddingsRequest{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(sent) != 2 {
+		t.Errorf("Result was incorrect, got: %d requests, want: %d.", len(sent), 2)
+	}
+	if !reflect.DeepEqual(resp.Vectors(), [][]float32{{2}, {3}}) || resp.Model != "m" || resp.Usage.TotalTokens != 0 {
+		t.Errorf("Result was incorrect, got: %+v.", resp)
+	}
+
+	// Case: Other models do not share cached vectors
+	if _, err := req.Embeddings(context.TODO(), "other", []string{"bb"}, EmbeddingsRequest{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(sent) != 3 {
+		t.Errorf("Result was incorrect, got: %d requests, want: %d.", len(sent), 3)
+	}
+}
@@ -18,7 +18,7 @@ func TestEmbeddings(t *testing.T) {
 	req.Debug = false
 
 	// Case: Embeddings Fails with no context
-	resp, err := req.Embeddings(nil, "", "", EmbeddingsRequest{}) //lint:ignore SA1012 nil context used intentionally
+	resp, err := req.Embeddings(nil, "", nil, EmbeddingsRequest{}) //lint:ignore SA1012 nil context used intentionally
 	if !reflect.DeepEqual(resp, EmbeddingsResponse{}) {
 		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, EmbeddingsResponse{})
 	}
@@ -27,7 +27,7 @@ func TestEmbeddings(t *testing.T) {
 	}
 
 	// Case: Completions Fails with no model
-	resp, err = req.Embeddings(context.TODO(), "", "", EmbeddingsRequest{})
+	resp, err = req.Embeddings(context.TODO(), "", nil, EmbeddingsRequest{})
 	if !reflect.DeepEqual(resp, EmbeddingsResponse{}) {
 		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, EmbeddingsResponse{})
 	}
@@ -36,7 +36,7 @@ func TestEmbeddings(t *testing.T) {
 	}
 
 	// Case: Completions Fails with no input
-	resp, err = req.Embeddings(context.TODO(), "a", "", EmbeddingsRequest{})
+	resp, err = req.Embeddings(context.TODO(), "a", nil, EmbeddingsRequest{})
 	if !reflect.DeepEqual(resp, EmbeddingsResponse{}) {
 		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, EmbeddingsResponse{})
 	}
@@ -44,8 +44,17 @@ func TestEmbeddings(t *testing.T) {
 		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "no prompt provided")
 	}
 
+	// Case: Embeddings Fails with an empty input
+	resp, err = req.Embeddings(context.TODO(), "a", []string{"b", ""}, EmbeddingsRequest{})
+	if !reflect.DeepEqual(resp, EmbeddingsResponse{}) {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, EmbeddingsResponse{})
+	}
+	if err == nil {
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "input 1 is empty")
+	}
+
 	// Case: Chat Completion Fails with invalid HTTP request
-	resp, err = req.Embeddings(context.TODO(), "a", "b", EmbeddingsRequest{})
+	resp, err = req.Embeddings(context.TODO(), "a", []string{"b"}, EmbeddingsRequest{})
 	if !reflect.DeepEqual(resp, EmbeddingsResponse{}) {
 		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, EmbeddingsResponse{})
 	}
@@ -60,7 +69,7 @@ func TestEmbeddings(t *testing.T) {
 
 	req.BaseURL = ts.URL
 
-	resp, err = req.Embeddings(context.TODO(), "a", "b", EmbeddingsRequest{})
+	resp, err = req.Embeddings(context.TODO(), "a", []string{"b"}, EmbeddingsRequest{})
 	if !reflect.DeepEqual(resp, EmbeddingsResponse{}) {
 		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, EmbeddingsResponse{})
 	}
@@ -78,7 +87,7 @@ func TestEmbeddings(t *testing.T) {
 
 	req.BaseURL = ts.URL
 
-	resp, err = req.Embeddings(context.TODO(), "a", "b", EmbeddingsRequest{})
+	resp, err = req.Embeddings(context.TODO(), "a", []string{"b"}, EmbeddingsRequest{})
 	if !reflect.DeepEqual(resp, EmbeddingsResponse{}) {
 		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, EmbeddingsResponse{})
 	}
@@ -88,8 +97,7 @@ func TestEmbeddings(t *testing.T) {
 
 	ts.Close()
 
-	// Case: Chat Completion Succeeds with HTTP 200
-	req.Debug = true
+	// Case: Embeddings Fails with HTTP 200 and a missing embedding
 	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		resp, _ := json.Marshal(EmbeddingsResponse{})
 		fmt.Fprintln(w, bytes.NewBuffer(resp))
@@ -97,10 +105,37 @@ func TestEmbeddings(t *testing.T) {
 
 	req.BaseURL = ts.URL
 
-	resp, err = req.Embeddings(context.TODO(), "a", "b", EmbeddingsRequest{})
+	resp, err = req.Embeddings(context.TODO(), "a", []string{"b"}, EmbeddingsRequest{})
 	if !reflect.DeepEqual(resp, EmbeddingsResponse{}) {
 		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, EmbeddingsResponse{})
 	}
+	if err == nil {
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "expected 1 embeddings, got 0")
+	}
+
+	ts.Close()
+
+	// Case: Chat Completion Succeeds with HTTP 200
+	req.Debug = true
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body EmbeddingsRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if !reflect.DeepEqual(body.Input, []string{"b", "c"}) {
+			t.Errorf("Result was incorrect, got: %v, want: %v.", body.Input, []string{"b", "c"})
+		}
+
+		fmt.Fprintln(w, `{"object":"list","model":"a","data":[{"object":"embedding","index":1,"embedding":[0.3,0.4]},{"object":"embedding","index":0,"embedding":[0.1,0.2]}],"usage":{"prompt_tokens":2,"total_tokens":2}}`)
+	}))
+
+	req.BaseURL = ts.URL
+
+	resp, err = req.Embeddings(context.TODO(), "a", []string{"b", "c"}, EmbeddingsRequest{})
+	if !reflect.DeepEqual(resp.Vectors(), [][]float32{{0.1, 0.2}, {0.3, 0.4}}) {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", resp.Vectors(), [][]float32{{0.1, 0.2}, {0.3, 0.4}})
+	}
+	if resp.Model != "a" || resp.Usage.TotalTokens != 2 {
+		t.Errorf("Result was incorrect, got: %+v.", resp)
+	}
 	if err != nil {
 		t.Errorf("Error was incorrect, got: %s, want: %v.", err, nil)
 	}
@@ -0,0 +1,415 @@
+package together
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type EndpointType string
+
+const (
+	EndpointTypeDedicated  EndpointType = "dedicated"
+	EndpointTypeServerless EndpointType = "serverless"
+)
+
+type EndpointState string
+
+const (
+	EndpointStatePending  EndpointState = "PENDING"
+	EndpointStateStarting EndpointState = "STARTING"
+	EndpointStateStarted  EndpointState = "STARTED"
+	EndpointStateStopping EndpointState = "STOPPING"
+	EndpointStateStopped  EndpointState = "STOPPED"
+	EndpointStateError    EndpointState = "ERROR"
+)
+
+// Transitional reports whether an endpoint in this state is still moving to
+// another state.
+func (s EndpointState) Transitional() bool {
+	switch s {
+	case EndpointStatePending, EndpointStateStarting, EndpointStateStopping:
+		return true
+	}
+	return false
+}
+
+// Requestable reports whether this state can be requested in UpdateEndpoint.
+// Only STARTED and STOPPED can be requested; the other states are reached
+// while the endpoint moves between them.
+func (s EndpointState) Requestable() bool {
+	return s == EndpointStateStarted || s == EndpointStateStopped
+}
+
+type Autoscaling struct {
+	MinReplicas int `json:"min_replicas"`
+	MaxReplicas int `json:"max_replicas"`
+}
+
+func (a Autoscaling) validate() error {
+	if a.MinReplicas < 0 || a.MaxReplicas < a.MinReplicas {
+		return fmt.Errorf("invalid autoscaling: %d to %d replicas", a.MinReplicas, a.MaxReplicas)
+	}
+	return nil
+}
+
+type CreateEndpointRequest struct {
+	DisplayName                string        `json:"display_name,omitempty"`
+	Model                      string        `json:"model"`
+	Hardware                   string        `json:"hardware"`    // Id of the hardware, see ListHardware.
+	Autoscaling                Autoscaling   `json:"autoscaling"` // Defaults to a single replica.
+	DisablePromptCache         bool          `json:"disable_prompt_cache,omitempty"`
+	DisableSpeculativeDecoding bool          `json:"disable_speculative_decoding,omitempty"`
+	State                      EndpointState `json:"state,omitempty"`            // STARTED or STOPPED; defaults to STARTED.
+	InactiveTimeout            *int          `json:"inactive_timeout,omitempty"` // Minutes of inactivity before the endpoint is stopped; 0 disables.
+}
+
+// UpdateEndpointRequest holds the fields to change. Empty fields are left
+// unchanged.
+type UpdateEndpointRequest struct {
+	DisplayName     string        `json:"display_name,omitempty"`
+	Hardware        string        `json:"hardware,omitempty"`
+	Autoscaling     *Autoscaling  `json:"autoscaling,omitempty"`
+	State           EndpointState `json:"state,omitempty"` // STARTED or STOPPED.
+	InactiveTimeout *int          `json:"inactive_timeout,omitempty"`
+}
+
+type Endpoint struct {
+	Object      string        `json:"object"`
+	Id          string        `json:"id"`
+	Name        string        `json:"name"` // Model name used to send requests to the endpoint.
+	DisplayName string        `json:"display_name"`
+	Model       string        `json:"model"`
+	Hardware    string        `json:"hardware"`
+	Type        EndpointType  `json:"type"`
+	Owner       string        `json:"owner"`
+	State       EndpointState `json:"state"`
+	Autoscaling Autoscaling   `json:"autoscaling"`
+	CreatedAt   string        `json:"created_at"` // RFC 3339 timestamp.
+}
+
+type EndpointListResponse struct {
+	Object string     `json:"object"`
+	Data   []Endpoint `json:"data"`
+}
+
+type HardwarePricing struct {
+	CentsPerMinute float64 `json:"cents_per_minute"`
+}
+
+type HardwareSpecs struct {
+	GPUType   string  `json:"gpu_type"`
+	GPULink   string  `json:"gpu_link"`
+	GPUMemory float64 `json:"gpu_memory"` // In GB.
+	GPUCount  int     `json:"gpu_count"`
+}
+
+// Common values of HardwareAvailability.Status.
+const (
+	HardwareAvailable    = "available"
+	HardwareUnavailable  = "unavailable"
+	HardwareInsufficient = "insufficient"
+)
+
+type HardwareAvailability struct {
+	Status string `json:"status"`
+}
+
+type Hardware struct {
+	Object       string                `json:"object"`
+	Id           string                `json:"id"`
+	Pricing      HardwarePricing       `json:"pricing"`
+	Specs        HardwareSpecs         `json:"specs"`
+	Availability *HardwareAvailability `json:"availability"` // Only set when listing hardware for a model.
+	UpdatedAt    string                `json:"updated_at"`   // RFC 3339 timestamp.
+}
+
+type HardwareListResponse struct {
+	Object string     `json:"object"`
+	Data   []Hardware `json:"data"`
+}
+
+type WaitForEndpointOptions struct {
+	PollInterval    time.Duration // Initial delay between polls; defaults to 5 seconds.
+	MaxPollInterval time.Duration // Upper bound of the growing delay; defaults to 1 minute.
+}
+
+// Create Endpoint is the endpoint for deploying a model on dedicated hardware.
+// Use WaitForEndpoint to wait until the endpoint has started.
+//
+// API Reference: https://docs.together.ai/reference/createendpoint
+func (api *API) CreateEndpoint(ctx context.Context, model, hardware string, request CreateEndpointRequest) (Endpoint, error) {
+	if ctx == nil {
+		return Endpoint{}, fmt.Errorf("no context provided")
+	}
+	if model == "" {
+		return Endpoint{}, fmt.Errorf("no model provided")
+	}
+	if hardware == "" {
+		return Endpoint{}, fmt.Errorf("no hardware provided")
+	}
+	if request.Autoscaling == (Autoscaling{}) {
+		request.Autoscaling = Autoscaling{MinReplicas: 1, MaxReplicas: 1}
+	}
+	if err := request.Autoscaling.validate(); err != nil {
+		return Endpoint{}, err
+	}
+	if request.State != "" && !request.State.Requestable() {
+		return Endpoint{}, fmt.Errorf("endpoint state %s cannot be requested", request.State)
+	}
+
+	request.Model = model
+	request.Hardware = hardware
+
+	uri := defaultBasePath + Version + "/endpoints"
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		return Endpoint{}, err
+	}
+
+	res, err := api.request(ctx, "POST", uri, bytes.NewBuffer(reqBody), nil)
+	if err != nil {
+		return Endpoint{}, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return Endpoint{}, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return Endpoint{}, newAPIError(res, body)
+	}
+
+	var endpoint Endpoint
+	err = json.Unmarshal(body, &endpoint)
+	if err != nil {
+		return Endpoint{}, err
+	}
+
+	return endpoint, nil
+}
+
+// List Endpoints is the endpoint for listing endpoints. An empty endpointType
+// lists endpoints of all types.
+//
+// API Reference: https://docs.together.ai/reference/listendpoints
+func (api *API) ListEndpoints(ctx context.Context, endpointType EndpointType) (EndpointListResponse, error) {
+	if ctx == nil {
+		return EndpointListResponse{}, fmt.Errorf("no context provided")
+	}
+
+	uri := defaultBasePath + Version + "/endpoints"
+	if endpointType != "" {
+		uri += "?type=" + url.QueryEscape(string(endpointType))
+	}
+
+	res, err := api.request(ctx, "GET", uri, nil, nil)
+	if err != nil {
+		return EndpointListResponse{}, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return EndpointListResponse{}, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return EndpointListResponse{}, newAPIError(res, body)
+	}
+
+	var endpointListResponse EndpointListResponse
+	err = json.Unmarshal(body, &endpointListResponse)
+	if err != nil {
+		return EndpointListResponse{}, err
+	}
+
+	return endpointListResponse, nil
+}
+
+// Get Endpoint is the endpoint for retrieving an endpoint.
+//
+// API Reference: https://docs.together.ai/reference/getendpoint
+func (api *API) GetEndpoint(ctx context.Context, id string) (Endpoint, error) {
+	return api.endpoint(ctx, "GET", id, nil)
+}
+
+// Update Endpoint is the endpoint for changing the display name, hardware,
+// autoscaling, state or inactive timeout of an endpoint.
+//
+// API Reference: https://docs.together.ai/reference/updateendpoint
+func (api *API) UpdateEndpoint(ctx context.Context, id string, request UpdateEndpointRequest) (Endpoint, error) {
+	if request.Autoscaling != nil {
+		if err := request.Autoscaling.validate(); err != nil {
+			return Endpoint{}, err
+		}
+	}
+	if request.State != "" && !request.State.Requestable() {
+		return Endpoint{}, fmt.Errorf("endpoint state %s cannot be requested", request.State)
+	}
+	return api.endpoint(ctx, "PATCH", id, request)
+}
+
+// StartEndpoint requests a stopped endpoint to start. Use WaitForEndpoint to
+// wait until it has started.
+func (api *API) StartEndpoint(ctx context.Context, id string) (Endpoint, error) {
+	return api.UpdateEndpoint(ctx, id, UpdateEndpointRequest{State: EndpointStateStarted})
+}
+
+// StopEndpoint requests a running endpoint to stop. Stopped endpoints are not
+// billed and can be started again.
+func (api *API) StopEndpoint(ctx context.Context, id string) (Endpoint, error) {
+	return api.UpdateEndpoint(ctx, id, UpdateEndpointRequest{State: EndpointStateStopped})
+}
+
+// Delete Endpoint is the endpoint for permanently deleting an endpoint.
+//
+// API Reference: https://docs.together.ai/reference/deleteendpoint
+func (api *API) DeleteEndpoint(ctx context.Context, id string) error {
+	if ctx == nil {
+		return fmt.Errorf("no context provided")
+	}
+	if id == "" {
+		return fmt.Errorf("no endpoint id provided")
+	}
+
+	uri := defaultBasePath + Version + "/endpoints/" + url.PathEscape(id)
+
+	res, err := api.request(ctx, "DELETE", uri, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNoContent {
+		return newAPIError(res, body)
+	}
+
+	return nil
+}
+
+// List Hardware is the endpoint for listing the hardware that endpoints can
+// be deployed on. If model is set, only compatible hardware is listed, along
+// with its current availability.
+//
+// API Reference: https://docs.together.ai/reference/listhardware
+func (api *API) ListHardware(ctx context.Context, model string) (HardwareListResponse, error) {
+	if ctx == nil {
+		return HardwareListResponse{}, fmt.Errorf("no context provided")
+	}
+
+	uri := defaultBasePath + Version + "/hardware"
+	if model != "" {
+		uri += "?model=" + url.QueryEscape(model)
+	}
+
+	res, err := api.request(ctx, "GET", uri, nil, nil)
+	if err != nil {
+		return HardwareListResponse{}, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return HardwareListResponse{}, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return HardwareListResponse{}, newAPIError(res, body)
+	}
+
+	var hardwareListResponse HardwareListResponse
+	err = json.Unmarshal(body, &hardwareListResponse)
+	if err != nil {
+		return HardwareListResponse{}, err
+	}
+
+	return hardwareListResponse, nil
+}
+
+// WaitForEndpoint polls an endpoint until it reaches the given state, and
+// returns it. It fails if the endpoint reaches the ERROR state.
+func (api *API) WaitForEndpoint(ctx context.Context, id string, state EndpointState, opts WaitForEndpointOptions) (Endpoint, error) {
+	if ctx == nil {
+		return Endpoint{}, fmt.Errorf("no context provided")
+	}
+	if id == "" {
+		return Endpoint{}, fmt.Errorf("no endpoint id provided")
+	}
+	if state == "" {
+		return Endpoint{}, fmt.Errorf("no endpoint state provided")
+	}
+
+	var endpoint Endpoint
+	err := poll(ctx, opts.PollInterval, opts.MaxPollInterval, func() (bool, bool, error) {
+		previous := endpoint.State
+
+		var err error
+		endpoint, err = api.GetEndpoint(ctx, id)
+		if err != nil {
+			return false, false, err
+		}
+		if endpoint.State == EndpointStateError && state != EndpointStateError {
+			return false, false, fmt.Errorf("endpoint %s failed", id)
+		}
+
+		return endpoint.State == state, endpoint.State != previous, nil
+	})
+	if err != nil {
+		return endpoint, err
+	}
+
+	return endpoint, nil
+}
+
+// endpoint sends a request for a single endpoint and decodes the returned
+// endpoint.
+func (api *API) endpoint(ctx context.Context, method, id string, request interface{}) (Endpoint, error) {
+	if ctx == nil {
+		return Endpoint{}, fmt.Errorf("no context provided")
+	}
+	if id == "" {
+		return Endpoint{}, fmt.Errorf("no endpoint id provided")
+	}
+
+	uri := defaultBasePath + Version + "/endpoints/" + url.PathEscape(id)
+
+	var reqBody interface{}
+	if request != nil {
+		data, err := json.Marshal(request)
+		if err != nil {
+			return Endpoint{}, err
+		}
+		reqBody = bytes.NewBuffer(data)
+	}
+
+	res, err := api.request(ctx, method, uri, reqBody, nil)
+	if err != nil {
+		return Endpoint{}, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return Endpoint{}, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return Endpoint{}, newAPIError(res, body)
+	}
+
+	var endpoint Endpoint
+	err = json.Unmarshal(body, &endpoint)
+	if err != nil {
+		return Endpoint{}, err
+	}
+
+	return endpoint, nil
+}
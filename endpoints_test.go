@@ -0,0 +1,266 @@
+package together
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEndpointState(t *testing.T) {
+	for _, state := range []EndpointState{EndpointStatePending, EndpointStateStarting, EndpointStateStopping} {
+		if !state.Transitional() || state.Requestable() {
+			t.Errorf("Result was incorrect for %s, got: transitional %v, requestable %v.", state, state.Transitional(), state.Requestable())
+		}
+	}
+	for _, state := range []EndpointState{EndpointStateStarted, EndpointStateStopped} {
+		if state.Transitional() || !state.Requestable() {
+			t.Errorf("Result was incorrect for %s, got: transitional %v, requestable %v.", state, state.Transitional(), state.Requestable())
+		}
+	}
+	if EndpointStateError.Transitional() || EndpointStateError.Requestable() {
+		t.Errorf("Result was incorrect for %s.", EndpointStateError)
+	}
+}
+
+func TestEndpoints(t *testing.T) {
+	req, _ := New("hunter2")
+	req.Client.RetryMax = 1
+	req.BaseURL = ""
+
+	// Case: Fails with no context
+	if _, err := req.CreateEndpoint(nil, "m", "h", CreateEndpointRequest{}); err == nil { //lint:ignore SA1012 nil context used intentionally
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no context provided")
+	}
+	if _, err := req.ListEndpoints(nil, ""); err == nil { //lint:ignore SA1012 nil context used intentionally
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no context provided")
+	}
+	if _, err := req.GetEndpoint(nil, "e"); err == nil { //lint:ignore SA1012 nil context used intentionally
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no context provided")
+	}
+	if err := req.DeleteEndpoint(nil, "e"); err == nil { //lint:ignore SA1012 nil context used intentionally
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no context provided")
+	}
+	if _, err := req.ListHardware(nil, ""); err == nil { //lint:ignore SA1012 nil context used intentionally
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no context provided")
+	}
+
+	// Case: Fails with missing arguments
+	if _, err := req.CreateEndpoint(context.TODO(), "", "h", CreateEndpointRequest{}); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no model provided")
+	}
+	if _, err := req.CreateEndpoint(context.TODO(), "m", "", CreateEndpointRequest{}); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no hardware provided")
+	}
+	if _, err := req.GetEndpoint(context.TODO(), ""); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no endpoint id provided")
+	}
+	if _, err := req.StartEndpoint(context.TODO(), ""); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no endpoint id provided")
+	}
+	if err := req.DeleteEndpoint(context.TODO(), ""); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no endpoint id provided")
+	}
+
+	// Case: Fails with invalid autoscaling or states
+	if _, err := req.CreateEndpoint(context.TODO(), "m", "h", CreateEndpointRequest{Autoscaling: Autoscaling{MinReplicas: 2, MaxReplicas: 1}}); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "invalid autoscaling: 2 to 1 replicas")
+	}
+	if _, err := req.CreateEndpoint(context.TODO(), "m", "h", CreateEndpointRequest{State: EndpointStateStarting}); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "endpoint state STARTING cannot be requested")
+	}
+	if _, err := req.UpdateEndpoint(context.TODO(), "e", UpdateEndpointRequest{Autoscaling: &Autoscaling{MinReplicas: -1}}); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "invalid autoscaling: -1 to 0 replicas")
+	}
+	if _, err := req.UpdateEndpoint(context.TODO(), "e", UpdateEndpointRequest{State: EndpointStateError}); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "endpoint state ERROR cannot be requested")
+	}
+
+	// Case: Fails with invalid HTTP request
+	if _, err := req.GetEndpoint(context.TODO(), "e"); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "!nil")
+	}
+
+	var mu sync.Mutex
+	var requests []string
+	var bodies []map[string]any
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		bodies = append(bodies, body)
+
+		switch r.Method + " " + r.URL.Path {
+		case "POST /v1/endpoints":
+			fmt.Fprintln(w, `{"object":"endpoint","id":"endpoint-1","name":"user/m-abc","model":"m","hardware":"1x_nvidia_h100_80gb_sxm","type":"dedicated","state":"PENDING","autoscaling":{"min_replicas":1,"max_replicas":1}}`)
+		case "GET /v1/endpoints":
+			fmt.Fprintln(w, `{"object":"list","data":[{"object":"endpoint","id":"endpoint-1","state":"STARTED","type":"dedicated"}]}`)
+		case "GET /v1/endpoints/endpoint-1":
+			fmt.Fprintln(w, `{"id":"endpoint-1","state":"STARTED"}`)
+		case "PATCH /v1/endpoints/endpoint-1":
+			state, _ := body["state"].(string)
+			fmt.Fprintf(w, `{"id":"endpoint-1","state":%q}`, state)
+		case "DELETE /v1/endpoints/endpoint-1":
+			w.WriteHeader(http.StatusNoContent)
+		case "GET /v1/hardware":
+			fmt.Fprintln(w, `{"object":"list","data":[{"object":"hardware","id":"2x_nvidia_a100_80gb_sxm","pricing":{"cents_per_minute":5.42},"specs":{"gpu_type":"a100-80gb","gpu_link":"sxm","gpu_memory":80,"gpu_count":2},"availability":{"status":"available"}}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintln(w, `{"error":{"message":"endpoint not found"}}`)
+		}
+	}))
+	defer ts.Close()
+
+	req.BaseURL = ts.URL
+	last := func() (string, map[string]any) {
+		mu.Lock()
+		defer mu.Unlock()
+		return requests[len(requests)-1], bodies[len(bodies)-1]
+	}
+
+	// Case: CreateEndpoint defaults to a single replica
+	endpoint, err := req.CreateEndpoint(context.TODO(), "m", "1x_nvidia_h100_80gb_sxm", CreateEndpointRequest{DisplayName: "test"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := Endpoint{Object: "endpoint", Id: "endpoint-1", Name: "user/m-abc", Model: "m", Hardware: "1x_nvidia_h100_80gb_sxm", Type: EndpointTypeDedicated, State: EndpointStatePending, Autoscaling: Autoscaling{MinReplicas: 1, MaxReplicas: 1}}
+	if !reflect.DeepEqual(endpoint, want) {
+		t.Errorf("Result was incorrect, got: %+v, want: %+v.", endpoint, want)
+	}
+	_, body := last()
+	wantBody := map[string]any{"display_name": "test", "model": "m", "hardware": "1x_nvidia_h100_80gb_sxm", "autoscaling": map[string]any{"min_replicas": float64(1), "max_replicas": float64(1)}}
+	if !reflect.DeepEqual(body, wantBody) {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", body, wantBody)
+	}
+
+	// Case: ListEndpoints filters by type
+	list, err := req.ListEndpoints(context.TODO(), EndpointTypeDedicated)
+	if err != nil || len(list.Data) != 1 || list.Data[0].State != EndpointStateStarted {
+		t.Errorf("Result was incorrect, got: %+v, %v.", list, err)
+	}
+	if uri, _ := last(); uri != "GET /v1/endpoints?type=dedicated" {
+		t.Errorf("Result was incorrect, got: %s, want: %s.", uri, "GET /v1/endpoints?type=dedicated")
+	}
+
+	// Case: GetEndpoint
+	endpoint, err = req.GetEndpoint(context.TODO(), "endpoint-1")
+	if err != nil || endpoint.State != EndpointStateStarted {
+		t.Errorf("Result was incorrect, got: %+v, %v.", endpoint, err)
+	}
+
+	// Case: GetEndpoint Fails with HTTP 404
+	if _, err := req.GetEndpoint(context.TODO(), "endpoint-2"); !IsNotFound(err) {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "endpoint not found")
+	}
+
+	// Case: UpdateEndpoint only sends the changed fields
+	_, err = req.UpdateEndpoint(context.TODO(), "endpoint-1", UpdateEndpointRequest{Autoscaling: &Autoscaling{MinReplicas: 1, MaxReplicas: 4}, Hardware: "h"})
+	if err != nil {
+		t.Errorf("Error was incorrect, got: %v, want: %v.", err, nil)
+	}
+	_, body = last()
+	wantBody = map[string]any{"hardware": "h", "autoscaling": map[string]any{"min_replicas": float64(1), "max_replicas": float64(4)}}
+	if !reflect.DeepEqual(body, wantBody) {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", body, wantBody)
+	}
+
+	// Case: StopEndpoint and StartEndpoint request the state
+	endpoint, err = req.StopEndpoint(context.TODO(), "endpoint-1")
+	if err != nil || endpoint.State != EndpointStateStopped {
+		t.Errorf("Result was incorrect, got: %+v, %v.", endpoint, err)
+	}
+	endpoint, err = req.StartEndpoint(context.TODO(), "endpoint-1")
+	if err != nil || endpoint.State != EndpointStateStarted {
+		t.Errorf("Result was incorrect, got: %+v, %v.", endpoint, err)
+	}
+
+	// Case: DeleteEndpoint accepts HTTP 204
+	if err := req.DeleteEndpoint(context.TODO(), "endpoint-1"); err != nil {
+		t.Errorf("Error was incorrect, got: %v, want: %v.", err, nil)
+	}
+	if err := req.DeleteEndpoint(context.TODO(), "endpoint-2"); !IsNotFound(err) {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "endpoint not found")
+	}
+
+	// Case: ListHardware for a model
+	hardware, err := req.ListHardware(context.TODO(), "org/model")
+	wantHardware := Hardware{
+		Object:       "hardware",
+		Id:           "2x_nvidia_a100_80gb_sxm",
+		Pricing:      HardwarePricing{CentsPerMinute: 5.42},
+		Specs:        HardwareSpecs{GPUType: "a100-80gb", GPULink: "sxm", GPUMemory: 80, GPUCount: 2},
+		Availability: &HardwareAvailability{Status: HardwareAvailable},
+	}
+	if err != nil || len(hardware.Data) != 1 || !reflect.DeepEqual(hardware.Data[0], wantHardware) {
+		t.Errorf("Result was incorrect, got: %+v, %v, want: %+v.", hardware, err, wantHardware)
+	}
+	if uri, _ := last(); uri != "GET /v1/hardware?model=org%2Fmodel" {
+		t.Errorf("Result was incorrect, got: %s, want: %s.", uri, "GET /v1/hardware?model=org%2Fmodel")
+	}
+}
+
+func TestWaitForEndpoint(t *testing.T) {
+	// The server reports the endpoint as starting for the first two polls.
+	var mu sync.Mutex
+	var polls int
+	var final string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		polls++
+		state := "STARTING"
+		if polls > 2 {
+			state = final
+		}
+		fmt.Fprintf(w, `{"id":"endpoint-1","state":%q}`, state)
+	}))
+	defer ts.Close()
+
+	reset := func(state string) {
+		mu.Lock()
+		defer mu.Unlock()
+		polls, final = 0, state
+	}
+
+	req, _ := New("hunter2", WithBaseURL(ts.URL), WithRetryMax(0))
+	opts := WaitForEndpointOptions{PollInterval: time.Millisecond, MaxPollInterval: 2 * time.Millisecond}
+
+	// Case: WaitForEndpoint Fails with no id or state
+	if _, err := req.WaitForEndpoint(context.TODO(), "", EndpointStateStarted, opts); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no endpoint id provided")
+	}
+	if _, err := req.WaitForEndpoint(context.TODO(), "endpoint-1", "", opts); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no endpoint state provided")
+	}
+
+	// Case: WaitForEndpoint returns the started endpoint
+	reset("STARTED")
+	endpoint, err := req.WaitForEndpoint(context.TODO(), "endpoint-1", EndpointStateStarted, opts)
+	if err != nil || endpoint.State != EndpointStateStarted {
+		t.Errorf("Result was incorrect, got: %+v, %v.", endpoint, err)
+	}
+
+	// Case: WaitForEndpoint Fails when the endpoint fails
+	reset("ERROR")
+	if _, err := req.WaitForEndpoint(context.TODO(), "endpoint-1", EndpointStateStarted, opts); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "endpoint endpoint-1 failed")
+	}
+
+	// Case: WaitForEndpoint stops when the context is done
+	reset("STARTING")
+	ctx, cancel := context.WithTimeout(context.TODO(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := req.WaitForEndpoint(ctx, "endpoint-1", EndpointStateStarted, opts); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Error was incorrect, got: %v, want: %v.", err, context.DeadlineExceeded)
+	}
+}
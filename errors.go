@@ -0,0 +1,130 @@
+package together
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned when the Together API responds with a non-successful
+// status code. Use errors.As to retrieve it from an error returned by the client.
+type APIError struct {
+	StatusCode int    // HTTP status code of the response.
+	Type       string // Together error type, e.g. "invalid_request_error".
+	Code       string // Together error code, e.g. "model_not_available".
+	Message    string // Human readable error message.
+	Param      string // Request parameter the error relates to, if any.
+	RequestID  string // Value of the X-Request-Id response header.
+	Body       []byte // Raw response body.
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	b.WriteString("HTTP request failed")
+	if e.StatusCode != 0 {
+		fmt.Fprintf(&b, ": %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	if e.Message != "" {
+		b.WriteString(": " + e.Message)
+	} else if len(e.Body) > 0 {
+		b.WriteString(": " + strings.TrimSpace(string(e.Body)))
+	}
+	if e.RequestID != "" {
+		b.WriteString(" (request id: " + e.RequestID + ")")
+	}
+	return b.String()
+}
+
+// errorObject is the error payload returned by the Together API. The code and
+// param fields are not consistently typed, so they are decoded as raw JSON.
+type errorObject struct {
+	Message string          `json:"message"`
+	Type    string          `json:"type"`
+	Param   json.RawMessage `json:"param"`
+	Code    json.RawMessage `json:"code"`
+}
+
+// newAPIError builds an *APIError from a failed response and its already read body.
+func newAPIError(res *http.Response, body []byte) error {
+	apiErr := &APIError{
+		StatusCode: res.StatusCode,
+		RequestID:  res.Header.Get("X-Request-Id"),
+		Body:       body,
+	}
+	apiErr.decode(body)
+	return apiErr
+}
+
+// decode populates the error fields from either an `{"error": {...}}` envelope,
+// an `{"error": "..."}` string, or a bare error object.
+func (e *APIError) decode(body []byte) {
+	var envelope struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return
+	}
+
+	var obj errorObject
+	var message string
+	switch {
+	case json.Unmarshal(envelope.Error, &message) == nil:
+		obj.Message = message
+	case json.Unmarshal(envelope.Error, &obj) == nil:
+	default:
+		_ = json.Unmarshal(body, &obj)
+	}
+
+	e.Message = obj.Message
+	e.Type = obj.Type
+	e.Param = rawString(obj.Param)
+	e.Code = rawString(obj.Code)
+}
+
+// rawString returns a JSON string or number as a plain string.
+func rawString(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	return string(raw)
+}
+
+// IsRateLimited reports whether err is an *APIError caused by exceeding a rate limit.
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+// IsAuth reports whether err is an *APIError caused by missing or invalid credentials.
+func IsAuth(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized, http.StatusForbidden)
+}
+
+// IsNotFound reports whether err is an *APIError caused by a missing resource or model.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsServerError reports whether err is an *APIError caused by a server side failure.
+func IsServerError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode >= http.StatusInternalServerError
+}
+
+func hasStatus(err error, codes ...int) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, code := range codes {
+		if apiErr.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
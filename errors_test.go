@@ -0,0 +1,103 @@
+package together
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Test decoding API errors from the supported error payloads.
+func TestNewAPIError(t *testing.T) {
+	res := &http.Response{StatusCode: http.StatusBadRequest, Header: make(http.Header)}
+	res.Header.Set("X-Request-Id", "req-1")
+
+	// Case: Error envelope with string code
+	err := newAPIError(res, []byte(`{"error":{"message":"bad model","type":"invalid_request_error","param":"model","code":"model_not_available"}}`))
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Result was incorrect, got: %T, want: %T.", err, apiErr)
+	}
+	if apiErr.StatusCode != 400 || apiErr.Message != "bad model" || apiErr.Type != "invalid_request_error" || apiErr.Param != "model" || apiErr.Code != "model_not_available" || apiErr.RequestID != "req-1" {
+		t.Errorf("Result was incorrect, got: %+v.", apiErr)
+	}
+
+	// Case: Error envelope with numeric code
+	err = newAPIError(res, []byte(`{"error":{"message":"bad","code":400}}`))
+	if errors.As(err, &apiErr); apiErr.Code != "400" {
+		t.Errorf("Result was incorrect, got: %s, want: %s.", apiErr.Code, "400")
+	}
+
+	// Case: Error envelope with string error
+	err = newAPIError(res, []byte(`{"error":"bad"}`))
+	if errors.As(err, &apiErr); apiErr.Message != "bad" {
+		t.Errorf("Result was incorrect, got: %s, want: %s.", apiErr.Message, "bad")
+	}
+
+	// Case: Bare error object
+	err = newAPIError(res, []byte(`{"message":"bad","type":"invalid_request_error"}`))
+	if errors.As(err, &apiErr); apiErr.Message != "bad" || apiErr.Type != "invalid_request_error" {
+		t.Errorf("Result was incorrect, got: %+v.", apiErr)
+	}
+
+	// Case: Non-JSON body is kept raw
+	err = newAPIError(res, []byte("Bad Request\n"))
+	if errors.As(err, &apiErr); apiErr.Message != "" || string(apiErr.Body) != "Bad Request\n" {
+		t.Errorf("Result was incorrect, got: %+v.", apiErr)
+	}
+	if err.Error() != "HTTP request failed: 400 Bad Request: Bad Request (request id: req-1)" {
+		t.Errorf("Result was incorrect, got: %s.", err.Error())
+	}
+}
+
+// Test classifying API errors.
+func TestAPIErrorHelpers(t *testing.T) {
+	cases := []struct {
+		status      int
+		rateLimited bool
+		auth        bool
+		notFound    bool
+		server      bool
+	}{
+		{http.StatusTooManyRequests, true, false, false, false},
+		{http.StatusUnauthorized, false, true, false, false},
+		{http.StatusForbidden, false, true, false, false},
+		{http.StatusNotFound, false, false, true, false},
+		{http.StatusServiceUnavailable, false, false, false, true},
+	}
+
+	for _, c := range cases {
+		err := fmt.Errorf("wrapped: %w", &APIError{StatusCode: c.status})
+		if IsRateLimited(err) != c.rateLimited || IsAuth(err) != c.auth || IsNotFound(err) != c.notFound || IsServerError(err) != c.server {
+			t.Errorf("Result was incorrect for status %d.", c.status)
+		}
+	}
+
+	if IsRateLimited(errors.New("HTTP request failed")) {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", true, false)
+	}
+}
+
+// Test that endpoints return an *APIError once retries are exhausted.
+func TestAPIErrorAfterRetries(t *testing.T) {
+	req, _ := New("hunter2")
+	req.Client.RetryMax = 1
+	req.Client.RetryWaitMin = time.Millisecond
+	req.Client.RetryWaitMax = time.Millisecond
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprintln(w, `{"error":{"message":"slow down","type":"rate_limit_exceeded"}}`)
+	}))
+	defer ts.Close()
+
+	req.BaseURL = ts.URL
+
+	_, err := req.ChatCompletions(context.TODO(), "a", []Message{{Role: "user", Content: "hi"}}, ChatCompletionsRequest{})
+	if !IsRateLimited(err) {
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "rate limited")
+	}
+}
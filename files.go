@@ -0,0 +1,344 @@
+package together
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+const FilePurposeFineTune = "fine-tune"
+
+type FileObject struct {
+	Id        string `json:"id"`
+	Object    string `json:"object"`
+	CreatedAt int64  `json:"created_at"` // Unix timestamp.
+	Filename  string `json:"filename"`
+	Bytes     int64  `json:"bytes"`
+	Purpose   string `json:"purpose"`
+	Processed bool   `json:"processed"`
+	FileType  string `json:"FileType"`  // The API capitalizes this key, e.g. "jsonl".
+	LineCount int    `json:"LineCount"` // The API capitalizes this key.
+}
+
+type FileListResponse struct {
+	Object string       `json:"object"`
+	Data   []FileObject `json:"data"`
+}
+
+type FileDeleteResponse struct {
+	Id      string `json:"id"`
+	Deleted bool   `json:"deleted"`
+}
+
+type UploadFileRequest struct {
+	Purpose  string                  // Defaults to FilePurposeFineTune.
+	Progress func(sent, total int64) // Called as the file is sent; total is -1 if the size is unknown.
+}
+
+// UploadFile is the endpoint for uploading a file, e.g. a JSONL training set.
+// The file is streamed rather than buffered in memory. A failed upload can
+// only be retried if file implements io.Seeker.
+//
+// API Reference: https://docs.together.ai/reference/upload-file
+func (api *API) UploadFile(ctx context.Context, filename string, file io.Reader, request UploadFileRequest) (FileObject, error) {
+	if ctx == nil {
+		return FileObject{}, fmt.Errorf("no context provided")
+	}
+	if filename == "" {
+		return FileObject{}, fmt.Errorf("no filename provided")
+	}
+	if file == nil {
+		return FileObject{}, fmt.Errorf("no file provided")
+	}
+
+	purpose := request.Purpose
+	if purpose == "" {
+		purpose = FilePurposeFineTune
+	}
+
+	body, err := newUploadBody(purpose, filename, file, request.Progress)
+	if err != nil {
+		return FileObject{}, err
+	}
+
+	uri := defaultBasePath + Version + "/files/upload"
+	headers := make(http.Header)
+	headers.Set("Content-Type", body.contentType)
+
+	res, err := api.request(ctx, "POST", uri, retryablehttp.ReaderFunc(body.reader), headers)
+	if err != nil {
+		return FileObject{}, err
+	}
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return FileObject{}, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return FileObject{}, newAPIError(res, resBody)
+	}
+
+	var fileObject FileObject
+	err = json.Unmarshal(resBody, &fileObject)
+	if err != nil {
+		return FileObject{}, err
+	}
+
+	return fileObject, nil
+}
+
+// ListFiles is the endpoint for listing uploaded files.
+//
+// API Reference: https://docs.together.ai/reference/get_files
+func (api *API) ListFiles(ctx context.Context) (FileListResponse, error) {
+	if ctx == nil {
+		return FileListResponse{}, fmt.Errorf("no context provided")
+	}
+
+	uri := defaultBasePath + Version + "/files"
+
+	res, err := api.request(ctx, "GET", uri, nil, nil)
+	if err != nil {
+		return FileListResponse{}, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return FileListResponse{}, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return FileListResponse{}, newAPIError(res, body)
+	}
+
+	var fileListResponse FileListResponse
+	err = json.Unmarshal(body, &fileListResponse)
+	if err != nil {
+		return FileListResponse{}, err
+	}
+
+	return fileListResponse, nil
+}
+
+// RetrieveFile is the endpoint for retrieving the metadata of a file.
+//
+// API Reference: https://docs.together.ai/reference/get_files-id
+func (api *API) RetrieveFile(ctx context.Context, id string) (FileObject, error) {
+	if ctx == nil {
+		return FileObject{}, fmt.Errorf("no context provided")
+	}
+	if id == "" {
+		return FileObject{}, fmt.Errorf("no file id provided")
+	}
+
+	uri := defaultBasePath + Version + "/files/" + url.PathEscape(id)
+
+	res, err := api.request(ctx, "GET", uri, nil, nil)
+	if err != nil {
+		return FileObject{}, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return FileObject{}, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return FileObject{}, newAPIError(res, body)
+	}
+
+	var fileObject FileObject
+	err = json.Unmarshal(body, &fileObject)
+	if err != nil {
+		return FileObject{}, err
+	}
+
+	return fileObject, nil
+}
+
+// DownloadFileContent is the endpoint for downloading the contents of a file.
+// The contents are streamed to w and the number of bytes written is returned.
+//
+// API Reference: https://docs.together.ai/reference/get_files-id-content
+func (api *API) DownloadFileContent(ctx context.Context, id string, w io.Writer) (int64, error) {
+	if ctx == nil {
+		return 0, fmt.Errorf("no context provided")
+	}
+	if id == "" {
+		return 0, fmt.Errorf("no file id provided")
+	}
+	if w == nil {
+		return 0, fmt.Errorf("no writer provided")
+	}
+
+	uri := defaultBasePath + Version + "/files/" + url.PathEscape(id) + "/content"
+
+	res, err := api.request(ctx, "GET", uri, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return 0, err
+		}
+		return 0, newAPIError(res, body)
+	}
+
+	return io.Copy(w, res.Body)
+}
+
+// DeleteFile is the endpoint for deleting a file.
+//
+// API Reference: https://docs.together.ai/reference/delete-files-id
+func (api *API) DeleteFile(ctx context.Context, id string) (FileDeleteResponse, error) {
+	if ctx == nil {
+		return FileDeleteResponse{}, fmt.Errorf("no context provided")
+	}
+	if id == "" {
+		return FileDeleteResponse{}, fmt.Errorf("no file id provided")
+	}
+
+	uri := defaultBasePath + Version + "/files/" + url.PathEscape(id)
+
+	res, err := api.request(ctx, "DELETE", uri, nil, nil)
+	if err != nil {
+		return FileDeleteResponse{}, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return FileDeleteResponse{}, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return FileDeleteResponse{}, newAPIError(res, body)
+	}
+
+	var fileDeleteResponse FileDeleteResponse
+	err = json.Unmarshal(body, &fileDeleteResponse)
+	if err != nil {
+		return FileDeleteResponse{}, err
+	}
+
+	return fileDeleteResponse, nil
+}
+
+// uploadBody streams a multipart form with a single file part. The form
+// fields and part boundaries are prepared up front so that the file itself is
+// never buffered.
+type uploadBody struct {
+	contentType string
+	head, tail  []byte
+
+	file     io.Reader
+	start    int64 // Offset of a seekable file when the upload started.
+	size     int64 // Bytes remaining in the file, or -1 if unknown.
+	read     bool  // Whether the file has been read, so a retry must rewind it.
+	progress func(sent, total int64)
+}
+
+func newUploadBody(purpose, filename string, file io.Reader, progress func(sent, total int64)) (*uploadBody, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	if err := mw.WriteField("purpose", purpose); err != nil {
+		return nil, err
+	}
+	if err := mw.WriteField("file_name", filename); err != nil {
+		return nil, err
+	}
+	if _, err := mw.CreateFormFile("file", filename); err != nil {
+		return nil, err
+	}
+	headLen := buf.Len()
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	b := &uploadBody{
+		contentType: mw.FormDataContentType(),
+		head:        buf.Bytes()[:headLen],
+		tail:        buf.Bytes()[headLen:],
+		file:        file,
+		size:        -1,
+		progress:    progress,
+	}
+
+	switch f := file.(type) {
+	case io.Seeker:
+		start, err := f.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, err
+		}
+		end, err := f.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := f.Seek(start, io.SeekStart); err != nil {
+			return nil, err
+		}
+		b.start, b.size = start, end-start
+	case interface{ Len() int }:
+		b.size = int64(f.Len())
+	}
+
+	return b, nil
+}
+
+// reader returns the body for one attempt, rewinding the file if a previous
+// attempt has read from it.
+func (b *uploadBody) reader() (io.Reader, error) {
+	if b.read {
+		seeker, ok := b.file.(io.Seeker)
+		if !ok {
+			return nil, errors.New("upload cannot be retried: file is not seekable")
+		}
+		if _, err := seeker.Seek(b.start, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+
+	r := io.MultiReader(bytes.NewReader(b.head), &progressReader{body: b}, bytes.NewReader(b.tail))
+	if b.size < 0 {
+		return r, nil
+	}
+	// Knowing the length avoids a chunked request body.
+	return &lenReader{Reader: r, len: len(b.head) + int(b.size) + len(b.tail)}, nil
+}
+
+type progressReader struct {
+	body *uploadBody
+	sent int64
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.body.file.Read(p)
+	if n > 0 {
+		r.body.read = true
+		r.sent += int64(n)
+		if r.body.progress != nil {
+			r.body.progress(r.sent, r.body.size)
+		}
+	}
+	return n, err
+}
+
+type lenReader struct {
+	io.Reader
+	len int
+}
+
+func (r *lenReader) Len() int {
+	return r.len
+}
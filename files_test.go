@@ -0,0 +1,187 @@
+package together
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestUploadFile(t *testing.T) {
+	req, _ := New("hunter2")
+
+	// Case: UploadFile Fails with no context
+	if _, err := req.UploadFile(nil, "a.jsonl", strings.NewReader("{}"), UploadFileRequest{}); err == nil { //lint:ignore SA1012 nil context used intentionally
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no context provided")
+	}
+
+	// Case: UploadFile Fails with no filename
+	if _, err := req.UploadFile(context.TODO(), "", strings.NewReader("{}"), UploadFileRequest{}); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no filename provided")
+	}
+
+	// The server fails the first attempt of every upload with HTTP 503.
+	var attempts int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/files/upload" || r.Method != "POST" {
+			t.Errorf("Result was incorrect, got: %s %s, want: %s.", r.Method, r.URL.Path, "POST /v1/files/upload")
+		}
+		if atomic.AddInt32(&attempts, 1)%2 == 1 {
+			io.Copy(io.Discard, r.Body)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"error":{"message":%q}}`, err.Error())
+			return
+		}
+		content, _ := io.ReadAll(file)
+
+		json.NewEncoder(w).Encode(FileObject{
+			Id:       "file-1",
+			Object:   "file",
+			Filename: header.Filename,
+			Bytes:    int64(len(content)),
+			Purpose:  r.FormValue("purpose"),
+			FileType: string(content),
+		})
+	}))
+	defer ts.Close()
+
+	req, _ = New("hunter2", WithBaseURL(ts.URL), WithRetryMax(1))
+	req.Client.RetryWaitMin = time.Millisecond
+	req.Client.RetryWaitMax = time.Millisecond
+
+	// Case: Seekable files are rewound when the upload is retried
+	var progress []int64
+	var total int64
+	resp, err := req.UploadFile(context.TODO(), "train.jsonl", strings.NewReader(`{"text":"hi"}`), UploadFileRequest{
+		Progress: func(sent, n int64) {
+			progress = append(progress, sent)
+			total = n
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := FileObject{Id: "file-1", Object: "file", Filename: "train.jsonl", Bytes: 13, Purpose: FilePurposeFineTune, FileType: `{"text":"hi"}`}
+	if !reflect.DeepEqual(resp, want) {
+		t.Errorf("Result was incorrect, got: %+v, want: %+v.", resp, want)
+	}
+	if total != 13 || len(progress) == 0 || progress[len(progress)-1] != 13 {
+		t.Errorf("Result was incorrect, got: %v of %d, want: %d.", progress, total, 13)
+	}
+
+	// Case: Unseekable files cannot be retried
+	_, err = req.UploadFile(context.TODO(), "train.jsonl", io.MultiReader(strings.NewReader("abc")), UploadFileRequest{})
+	if err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "upload cannot be retried")
+	}
+
+	// Case: Unseekable files are streamed with an unknown size
+	req.Client.RetryMax = 0
+	atomic.StoreInt32(&attempts, 1)
+	total = 0
+	resp, err = req.UploadFile(context.TODO(), "train.jsonl", io.MultiReader(strings.NewReader("abc")), UploadFileRequest{
+		Purpose:  "other",
+		Progress: func(sent, n int64) { total = n },
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.Purpose != "other" || resp.Bytes != 3 || total != -1 {
+		t.Errorf("Result was incorrect, got: %+v, total: %d.", resp, total)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	req, _ := New("hunter2")
+	req.Client.RetryMax = 1
+	req.BaseURL = ""
+
+	// Case: Fails with no context
+	if _, err := req.ListFiles(nil); err == nil { //lint:ignore SA1012 nil context used intentionally
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no context provided")
+	}
+
+	// Case: Fails with no file id
+	if _, err := req.RetrieveFile(context.TODO(), ""); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no file id provided")
+	}
+	if _, err := req.DownloadFileContent(context.TODO(), "", io.Discard); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no file id provided")
+	}
+	if _, err := req.DeleteFile(context.TODO(), ""); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no file id provided")
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v1/files":
+			fmt.Fprintln(w, `{"object":"list","data":[{"id":"file-1","object":"file","filename":"a.jsonl","bytes":5,"purpose":"fine-tune","processed":true,"FileType":"jsonl","LineCount":2}]}`)
+		case "GET /v1/files/file-1":
+			fmt.Fprintln(w, `{"id":"file-1","object":"file","filename":"a.jsonl","bytes":5}`)
+		case "GET /v1/files/file-1/content":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			fmt.Fprint(w, "{}\n{}\n")
+		case "DELETE /v1/files/file-1":
+			fmt.Fprintln(w, `{"id":"file-1","deleted":true}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintln(w, `{"error":{"message":"file not found"}}`)
+		}
+	}))
+	defer ts.Close()
+
+	req.BaseURL = ts.URL
+
+	// Case: ListFiles
+	list, err := req.ListFiles(context.TODO())
+	want := FileObject{Id: "file-1", Object: "file", Filename: "a.jsonl", Bytes: 5, Purpose: "fine-tune", Processed: true, FileType: "jsonl", LineCount: 2}
+	if err != nil || len(list.Data) != 1 || !reflect.DeepEqual(list.Data[0], want) {
+		t.Errorf("Result was incorrect, got: %+v, %v, want: %+v.", list, err, want)
+	}
+
+	// Case: RetrieveFile
+	file, err := req.RetrieveFile(context.TODO(), "file-1")
+	if err != nil || file.Id != "file-1" || file.Bytes != 5 {
+		t.Errorf("Result was incorrect, got: %+v, %v.", file, err)
+	}
+
+	// Case: RetrieveFile Fails with HTTP 404
+	_, err = req.RetrieveFile(context.TODO(), "file-2")
+	if !IsNotFound(err) {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "file not found")
+	}
+
+	// Case: DownloadFileContent streams to the writer
+	var buf bytes.Buffer
+	n, err := req.DownloadFileContent(context.TODO(), "file-1", &buf)
+	if err != nil || n != 6 || buf.String() != "{}\n{}\n" {
+		t.Errorf("Result was incorrect, got: %q (%d), %v.", buf.String(), n, err)
+	}
+
+	// Case: DownloadFileContent Fails with HTTP 404
+	buf.Reset()
+	_, err = req.DownloadFileContent(context.TODO(), "file-2", &buf)
+	if !IsNotFound(err) || buf.Len() != 0 {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "file not found")
+	}
+
+	// Case: DeleteFile
+	deleted, err := req.DeleteFile(context.TODO(), "file-1")
+	if err != nil || !reflect.DeepEqual(deleted, FileDeleteResponse{Id: "file-1", Deleted: true}) {
+		t.Errorf("Result was incorrect, got: %+v, %v.", deleted, err)
+	}
+}
@@ -1,6 +1,7 @@
 package together
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -9,154 +10,321 @@ import (
 	"net/url"
 )
 
-type FineTuningResponse struct {
-	Status     string   `json:"status"`
-	Prompt     []string `json:"prompt"`
-	Model      string   `json:"model"`
-	ModelOwner string   `json:"model_owner"`
-	Tags       Tags     `json:"tags"` // API Documentation states this is an object, but does not define it or provide an example.
-	NumReturns int      `json:"num_returns"`
-	Args       Args     `json:"args"`
-	Subjobs    []Subjob `json:"subjobs"` // API Documentation states this is an array, but does not define it or provide an example.
-	Output     Output   `json:"output"`
+type FineTuneStatus string
+
+const (
+	FineTuneStatusPending         FineTuneStatus = "pending"
+	FineTuneStatusQueued          FineTuneStatus = "queued"
+	FineTuneStatusRunning         FineTuneStatus = "running"
+	FineTuneStatusCompressing     FineTuneStatus = "compressing"
+	FineTuneStatusUploading       FineTuneStatus = "uploading"
+	FineTuneStatusCancelRequested FineTuneStatus = "cancel_requested"
+	FineTuneStatusCancelled       FineTuneStatus = "cancelled"
+	FineTuneStatusError           FineTuneStatus = "error"
+	FineTuneStatusCompleted       FineTuneStatus = "completed"
+)
+
+// Terminal reports whether a job with this status will not change anymore.
+func (s FineTuneStatus) Terminal() bool {
+	switch s {
+	case FineTuneStatusCompleted, FineTuneStatusCancelled, FineTuneStatusError:
+		return true
+	}
+	return false
 }
 
-type Args struct {
-	Model       string  `json:"model"`
-	Prompt      string  `json:"prompt"`
-	Temperature float64 `json:"temperature"`
-	TopP        float64 `json:"top_p"`
-	TopK        int     `json:"top_k"`
-	MaxTokens   int     `json:"max_tokens"`
+type FineTuneRequest struct {
+	TrainingFile         string  `json:"training_file"` // Id of an uploaded file.
+	ValidationFile       string  `json:"validation_file,omitempty"`
+	Model                string  `json:"model"`
+	NEpochs              int     `json:"n_epochs,omitempty"`
+	NCheckpoints         int     `json:"n_checkpoints,omitempty"`
+	NEvals               int     `json:"n_evals,omitempty"`
+	BatchSize            int     `json:"batch_size,omitempty"`
+	LearningRate         float64 `json:"learning_rate,omitempty"`
+	WarmupRatio          float64 `json:"warmup_ratio,omitempty"`
+	Lora                 bool    `json:"lora,omitempty"`
+	LoraR                int     `json:"lora_r,omitempty"`
+	LoraAlpha            int     `json:"lora_alpha,omitempty"`
+	LoraDropout          float64 `json:"lora_dropout,omitempty"`
+	LoraTrainableModules string  `json:"lora_trainable_modules,omitempty"` // Comma separated, e.g. "q_proj,v_proj".
+	Suffix               string  `json:"suffix,omitempty"`                 // Appended to the name of the fine-tuned model.
+	WandbAPIKey          string  `json:"wandb_api_key,omitempty"`
 }
 
-type Output struct {
-	Choices        []Choice `json:"choices"`
-	RawComputeTime float64  `json:"raw_compute_time"`
-	ResultType     string   `json:"result_type"`
+type FineTuneJob struct {
+	Id                   string          `json:"id"`
+	Status               FineTuneStatus  `json:"status"`
+	Model                string          `json:"model"`
+	OutputName           string          `json:"output_name"` // Name of the fine-tuned model.
+	TrainingFile         string          `json:"training_file"`
+	ValidationFile       string          `json:"validation_file"`
+	NEpochs              int             `json:"n_epochs"`
+	NCheckpoints         int             `json:"n_checkpoints"`
+	NEvals               int             `json:"n_evals"`
+	BatchSize            int             `json:"batch_size"`
+	LearningRate         float64         `json:"learning_rate"`
+	WarmupRatio          float64         `json:"warmup_ratio"`
+	Lora                 bool            `json:"lora"`
+	LoraR                int             `json:"lora_r"`
+	LoraAlpha            int             `json:"lora_alpha"`
+	LoraDropout          float64         `json:"lora_dropout"`
+	LoraTrainableModules string          `json:"lora_trainable_modules"`
+	Suffix               string          `json:"suffix"`
+	EpochsCompleted      int             `json:"epochs_completed"`
+	TokenCount           int             `json:"token_count"`
+	ParamCount           int64           `json:"param_count"`
+	TotalPrice           int64           `json:"total_price"`
+	QueueDepth           int             `json:"queue_depth"`
+	WandbProjectName     string          `json:"wandb_project_name"`
+	WandbURL             string          `json:"wandb_url"`
+	CreatedAt            string          `json:"created_at"` // RFC 3339 timestamp.
+	UpdatedAt            string          `json:"updated_at"` // RFC 3339 timestamp.
+	Events               []FineTuneEvent `json:"events"`
 }
 
-type Choice struct {
-	FinishReason string `json:"finish_reason"`
-	Index        int    `json:"index"`
-	Text         string `json:"text"`
+type FineTuneListResponse struct {
+	Data []FineTuneJob `json:"data"`
 }
 
-type Tags struct{}   // TODO: define Tags.
-type Subjob struct{} // TODO: define Subjob.
+// Common values of FineTuneEvent.Type.
+const (
+	FineTuneEventTrainingStart    = "training_start"
+	FineTuneEventCheckpointSave   = "checkpoint_save"
+	FineTuneEventEpochComplete    = "epoch_complete"
+	FineTuneEventTrainingComplete = "training_complete"
+	FineTuneEventJobComplete      = "job_complete"
+	FineTuneEventJobError         = "job_error"
+)
+
+type FineTuneEvent struct {
+	Object         string `json:"object"`
+	CreatedAt      string `json:"created_at"` // RFC 3339 timestamp.
+	Level          string `json:"level"`
+	Message        string `json:"message"`
+	Type           string `json:"type"`
+	Step           int    `json:"step"`
+	TotalSteps     int    `json:"total_steps"`
+	CheckpointPath string `json:"checkpoint_path"`
+	ModelPath      string `json:"model_path"`
+	Hash           string `json:"hash"`
+	WandbURL       string `json:"wandb_url"`
+}
+
+type FineTuneEventsResponse struct {
+	Data []FineTuneEvent `json:"data"`
+}
+
+type FineTuneCheckpoint struct {
+	Step           int    `json:"step"`
+	Path           string `json:"path"`
+	CheckpointType string `json:"checkpoint_type"`
+	CreatedAt      string `json:"created_at"` // RFC 3339 timestamp.
+}
+
+type FineTuneCheckpointsResponse struct {
+	Data []FineTuneCheckpoint `json:"data"`
+}
+
+// Create Fine-tune is the endpoint for starting a fine-tuning job on an
+// uploaded training file.
+//
+// API Reference: https://docs.together.ai/reference/post_fine-tunes
+func (api *API) CreateFineTune(ctx context.Context, model, trainingFile string, request FineTuneRequest) (FineTuneJob, error) {
+	if ctx == nil {
+		return FineTuneJob{}, fmt.Errorf("no context provided")
+	}
+	if model == "" {
+		return FineTuneJob{}, fmt.Errorf("no model provided")
+	}
+	if trainingFile == "" {
+		return FineTuneJob{}, fmt.Errorf("no training file provided")
+	}
+
+	request.Model = model
+	request.TrainingFile = trainingFile
+
+	uri := defaultBasePath + Version + "/fine-tunes"
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		return FineTuneJob{}, err
+	}
+
+	res, err := api.request(ctx, "POST", uri, bytes.NewBuffer(reqBody), nil)
+	if err != nil {
+		return FineTuneJob{}, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return FineTuneJob{}, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return FineTuneJob{}, newAPIError(res, body)
+	}
+
+	var fineTuneJob FineTuneJob
+	err = json.Unmarshal(body, &fineTuneJob)
+	if err != nil {
+		return FineTuneJob{}, err
+	}
+
+	return fineTuneJob, nil
+}
 
-// List Running Instances is the endpoint for listing running fine-tuning instances.
+// List Fine-tunes is the endpoint for listing fine-tuning jobs.
 //
-// API Reference: https://docs.together.ai/reference/instances
-func (api *API) ListRunningInstances(ctx context.Context) (FineTuningResponse, error) {
+// API Reference: https://docs.together.ai/reference/get_fine-tunes
+func (api *API) ListFineTunes(ctx context.Context) (FineTuneListResponse, error) {
 	if ctx == nil {
-		return FineTuningResponse{}, fmt.Errorf("no context provided")
+		return FineTuneListResponse{}, fmt.Errorf("no context provided")
 	}
 
-	uri := defaultBasePath + "instances"
+	uri := defaultBasePath + Version + "/fine-tunes"
 
 	res, err := api.request(ctx, "GET", uri, nil, nil)
 	if err != nil {
-		return FineTuningResponse{}, err
+		return FineTuneListResponse{}, err
 	}
 	defer res.Body.Close()
 
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
-		return FineTuningResponse{}, err
+		return FineTuneListResponse{}, err
 	}
 	if res.StatusCode != http.StatusOK {
-		return FineTuningResponse{}, fmt.Errorf("HTTP request failed: %s", string(body))
+		return FineTuneListResponse{}, newAPIError(res, body)
 	}
 
-	var fineTuningResponse FineTuningResponse
-	err = json.Unmarshal(body, &fineTuningResponse)
+	var fineTuneListResponse FineTuneListResponse
+	err = json.Unmarshal(body, &fineTuneListResponse)
 	if err != nil {
-		return FineTuningResponse{}, err
+		return FineTuneListResponse{}, err
 	}
 
-	return fineTuningResponse, nil
+	return fineTuneListResponse, nil
 }
 
-// Start Fine-tuned Instance is the endpoint for starting a fine-tuned model.
+// Retrieve Fine-tune is the endpoint for retrieving a fine-tuning job.
 //
-// API Reference: https://docs.together.ai/reference/instances-start
-func (api *API) StartFineTunedInstance(ctx context.Context, name string) (FineTuningResponse, error) {
+// API Reference: https://docs.together.ai/reference/get_fine-tunes-id
+func (api *API) RetrieveFineTune(ctx context.Context, id string) (FineTuneJob, error) {
+	return api.fineTuneJob(ctx, "GET", id, "")
+}
+
+// Cancel Fine-tune is the endpoint for cancelling a running fine-tuning job.
+//
+// API Reference: https://docs.together.ai/reference/post_fine-tunes-id-cancel
+func (api *API) CancelFineTune(ctx context.Context, id string) (FineTuneJob, error) {
+	return api.fineTuneJob(ctx, "POST", id, "/cancel")
+}
+
+// List Fine-tune Events is the endpoint for listing the events of a
+// fine-tuning job, such as training progress and saved checkpoints, in the
+// order they occurred.
+//
+// API Reference: https://docs.together.ai/reference/get_fine-tunes-id-events
+func (api *API) ListFineTuneEvents(ctx context.Context, id string) (FineTuneEventsResponse, error) {
 	if ctx == nil {
-		return FineTuningResponse{}, fmt.Errorf("no context provided")
+		return FineTuneEventsResponse{}, fmt.Errorf("no context provided")
 	}
-	if name == "" {
-		return FineTuningResponse{}, fmt.Errorf("no name provided")
+	if id == "" {
+		return FineTuneEventsResponse{}, fmt.Errorf("no fine-tune id provided")
 	}
 
-	uri := defaultBasePath + "instances/start?model=" + url.QueryEscape(name)
+	uri := defaultBasePath + Version + "/fine-tunes/" + url.PathEscape(id) + "/events"
 
-	res, err := api.request(ctx, "POST", uri, nil, nil)
+	res, err := api.request(ctx, "GET", uri, nil, nil)
 	if err != nil {
-		return FineTuningResponse{}, err
+		return FineTuneEventsResponse{}, err
 	}
 	defer res.Body.Close()
 
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
-		return FineTuningResponse{}, err
+		return FineTuneEventsResponse{}, err
 	}
 	if res.StatusCode != http.StatusOK {
-		return FineTuningResponse{}, fmt.Errorf("HTTP request failed: %s", string(body))
+		return FineTuneEventsResponse{}, newAPIError(res, body)
 	}
 
-	var fineTuningResponse FineTuningResponse
-	err = json.Unmarshal(body, &fineTuningResponse)
+	var fineTuneEventsResponse FineTuneEventsResponse
+	err = json.Unmarshal(body, &fineTuneEventsResponse)
 	if err != nil {
-		return FineTuningResponse{}, err
+		return FineTuneEventsResponse{}, err
 	}
 
-	return fineTuningResponse, nil
+	return fineTuneEventsResponse, nil
 }
 
-// Stop Fine-tuned Instance is the endpoint for stopping a fine-tuned model.
+// List Checkpoints is the endpoint for listing the checkpoints saved during a
+// fine-tuning job.
 //
-// API Reference: https://docs.together.ai/reference/instances-stop
-func (api *API) StopFineTunedInstance(ctx context.Context, name string) (FineTuningResponse, error) {
+// API Reference: https://docs.together.ai/reference/get_fine-tunes-id-checkpoints
+func (api *API) ListCheckpoints(ctx context.Context, id string) (FineTuneCheckpointsResponse, error) {
 	if ctx == nil {
-		return FineTuningResponse{}, fmt.Errorf("no context provided")
+		return FineTuneCheckpointsResponse{}, fmt.Errorf("no context provided")
 	}
-	if name == "" {
-		return FineTuningResponse{}, fmt.Errorf("no name provided")
+	if id == "" {
+		return FineTuneCheckpointsResponse{}, fmt.Errorf("no fine-tune id provided")
 	}
 
-	uri := defaultBasePath + "instances/stop?model=" + url.QueryEscape(name)
+	uri := defaultBasePath + Version + "/fine-tunes/" + url.PathEscape(id) + "/checkpoints"
 
-	res, err := api.request(ctx, "POST", uri, nil, nil)
+	res, err := api.request(ctx, "GET", uri, nil, nil)
 	if err != nil {
-		return FineTuningResponse{}, err
+		return FineTuneCheckpointsResponse{}, err
 	}
 	defer res.Body.Close()
 
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
-		return FineTuningResponse{}, err
+		return FineTuneCheckpointsResponse{}, err
 	}
 	if res.StatusCode != http.StatusOK {
-		return FineTuningResponse{}, fmt.Errorf("HTTP request failed: %s", string(body))
+		return FineTuneCheckpointsResponse{}, newAPIError(res, body)
 	}
 
-	var fineTuningResponse FineTuningResponse
-	err = json.Unmarshal(body, &fineTuningResponse)
+	var fineTuneCheckpointsResponse FineTuneCheckpointsResponse
+	err = json.Unmarshal(body, &fineTuneCheckpointsResponse)
 	if err != nil {
-		return FineTuningResponse{}, err
+		return FineTuneCheckpointsResponse{}, err
 	}
 
-	return fineTuningResponse, nil
+	return fineTuneCheckpointsResponse, nil
 }
 
-// TODO: Looking at the Python CLI, there are clearly undocumented API endpoints for
-// Create
-// Retrieve
-// Monitor
-// Status
-// Checkpoints
-// Download
-//
-// As these are undocumented, they're likely to change or be removed at any time.
-// Therefore adding these endpoints is not a priority.
+// fineTuneJob sends a request for a single job and decodes the returned job.
+func (api *API) fineTuneJob(ctx context.Context, method, id, action string) (FineTuneJob, error) {
+	if ctx == nil {
+		return FineTuneJob{}, fmt.Errorf("no context provided")
+	}
+	if id == "" {
+		return FineTuneJob{}, fmt.Errorf("no fine-tune id provided")
+	}
+
+	uri := defaultBasePath + Version + "/fine-tunes/" + url.PathEscape(id) + action
+
+	res, err := api.request(ctx, method, uri, nil, nil)
+	if err != nil {
+		return FineTuneJob{}, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return FineTuneJob{}, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return FineTuneJob{}, newAPIError(res, body)
+	}
+
+	var fineTuneJob FineTuneJob
+	err = json.Unmarshal(body, &fineTuneJob)
+	if err != nil {
+		return FineTuneJob{}, err
+	}
+
+	return fineTuneJob, nil
+}
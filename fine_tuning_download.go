@@ -0,0 +1,286 @@
+package together
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultDownloadRetries = 3
+	downloadRetryWait      = time.Second
+	downloadChunkSize      = 32 << 10
+)
+
+// ErrChecksumMismatch is returned when a downloaded artifact does not match
+// the expected checksum.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+type DownloadFineTuneOptions struct {
+	CheckpointStep int                        // Download the checkpoint saved at this step instead of the final model.
+	SHA256         string                     // Expected hex encoded SHA-256 of the artifact; verified if set.
+	Retries        int                        // Times an interrupted download is resumed; 0 means 3 and a negative value disables resuming.
+	Progress       func(written, total int64) // Called as data is written; total is -1 if the size is unknown.
+}
+
+type DownloadResult struct {
+	Filename string // Name suggested by the server, if any.
+	Bytes    int64  // Size of the artifact.
+	SHA256   string // Hex encoded SHA-256 of the artifact.
+}
+
+// DownloadFineTune is the endpoint for downloading the weights of a fine-tuned
+// model. The artifact is streamed to w. If the connection is interrupted, the
+// download is resumed with an HTTP Range request.
+//
+// API Reference: https://docs.together.ai/reference/get_finetune-download
+func (api *API) DownloadFineTune(ctx context.Context, id string, w io.Writer, opts DownloadFineTuneOptions) (DownloadResult, error) {
+	if ctx == nil {
+		return DownloadResult{}, fmt.Errorf("no context provided")
+	}
+	if id == "" {
+		return DownloadResult{}, fmt.Errorf("no fine-tune id provided")
+	}
+	if w == nil {
+		return DownloadResult{}, fmt.Errorf("no writer provided")
+	}
+
+	return api.downloadFineTune(ctx, id, w, 0, sha256.New(), opts)
+}
+
+// DownloadFineTuneFile downloads the weights of a fine-tuned model to path.
+// Data is written to path + ".part" first, and a partial file left behind by
+// an earlier attempt is resumed rather than downloaded again, so the options
+// must not change between attempts. The file is renamed to path once the
+// download is complete and its checksum is verified.
+func (api *API) DownloadFineTuneFile(ctx context.Context, id, path string, opts DownloadFineTuneOptions) (DownloadResult, error) {
+	if ctx == nil {
+		return DownloadResult{}, fmt.Errorf("no context provided")
+	}
+	if id == "" {
+		return DownloadResult{}, fmt.Errorf("no fine-tune id provided")
+	}
+	if path == "" {
+		return DownloadResult{}, fmt.Errorf("no path provided")
+	}
+
+	part := path + ".part"
+	f, err := os.OpenFile(part, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return DownloadResult{}, err
+	}
+
+	// Hashing the partial file also moves the offset to its end, where the
+	// download continues.
+	h := sha256.New()
+	offset, err := io.Copy(h, f)
+	if err != nil {
+		f.Close()
+		return DownloadResult{}, err
+	}
+
+	result, err := api.downloadFineTune(ctx, id, f, offset, h, opts)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// A corrupt partial file would fail the same way on every attempt.
+		if errors.Is(err, ErrChecksumMismatch) {
+			os.Remove(part)
+		}
+		return result, err
+	}
+
+	return result, os.Rename(part, path)
+}
+
+// downloadFineTune writes the artifact to w, starting at offset. h must
+// already contain the first offset bytes of the artifact.
+func (api *API) downloadFineTune(ctx context.Context, id string, w io.Writer, offset int64, h hash.Hash, opts DownloadFineTuneOptions) (DownloadResult, error) {
+	// Unlike EmbedAllOptions.Retries, zero selects the default so that
+	// downloads resume unless resuming is disabled explicitly.
+	retries := opts.Retries
+	if retries == 0 {
+		retries = defaultDownloadRetries
+	}
+	retries = max(retries, 0)
+
+	uri := defaultBasePath + Version + "/finetune/download?ft_id=" + url.QueryEscape(id)
+	if opts.CheckpointStep > 0 {
+		uri += "&checkpoint_step=" + strconv.Itoa(opts.CheckpointStep)
+	}
+
+	result := DownloadResult{Bytes: offset}
+	total := int64(-1)
+
+	for attempt := 0; ; attempt++ {
+		err := api.downloadAttempt(ctx, uri, w, h, &result, &total, opts.Progress)
+		if err == nil {
+			break
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return result, ctxErr
+		}
+
+		var interrupted *interruptedError
+		if !errors.As(err, &interrupted) || attempt >= retries {
+			return result, err
+		}
+
+		timer := time.NewTimer(downloadRetryWait * time.Duration(attempt+1))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if total >= 0 && result.Bytes != total {
+		return result, fmt.Errorf("incomplete download: got %d of %d bytes", result.Bytes, total)
+	}
+
+	result.SHA256 = hex.EncodeToString(h.Sum(nil))
+	if opts.SHA256 != "" && !strings.EqualFold(opts.SHA256, result.SHA256) {
+		return result, fmt.Errorf("%w: got %s, want %s", ErrChecksumMismatch, result.SHA256, opts.SHA256)
+	}
+
+	return result, nil
+}
+
+// interruptedError marks a download that failed while reading the response
+// body and can be resumed.
+type interruptedError struct {
+	err error
+}
+
+func (e *interruptedError) Error() string {
+	return "download interrupted: " + e.err.Error()
+}
+
+func (e *interruptedError) Unwrap() error {
+	return e.err
+}
+
+// downloadAttempt requests the remainder of the artifact and copies it to w
+// and h, updating result and total as data arrives.
+func (api *API) downloadAttempt(ctx context.Context, uri string, w io.Writer, h hash.Hash, result *DownloadResult, total *int64, progress func(written, total int64)) error {
+	headers := make(http.Header)
+	if result.Bytes > 0 {
+		headers.Set("Range", fmt.Sprintf("bytes=%d-", result.Bytes))
+	}
+
+	res, err := api.request(ctx, "GET", uri, nil, headers)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	// Bytes already written that the server sends again.
+	var skip int64
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		skip = result.Bytes
+		if res.ContentLength >= 0 {
+			*total = res.ContentLength
+		}
+	case http.StatusPartialContent:
+		start, size, ok := parseContentRange(res.Header.Get("Content-Range"))
+		if !ok || start != result.Bytes {
+			return fmt.Errorf("unexpected Content-Range %q", res.Header.Get("Content-Range"))
+		}
+		*total = size
+	case http.StatusRequestedRangeNotSatisfiable:
+		// Resuming a partial file that was already complete.
+		if _, size, ok := parseContentRange(res.Header.Get("Content-Range")); ok && size == result.Bytes {
+			*total = size
+			return nil
+		}
+		fallthrough
+	default:
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return err
+		}
+		return newAPIError(res, body)
+	}
+
+	if result.Filename == "" {
+		if _, params, err := mime.ParseMediaType(res.Header.Get("Content-Disposition")); err == nil {
+			result.Filename = params["filename"]
+		}
+	}
+
+	if skip > 0 {
+		if _, err := io.CopyN(io.Discard, res.Body, skip); err != nil {
+			return &interruptedError{err: err}
+		}
+	}
+
+	buf := make([]byte, downloadChunkSize)
+	for {
+		n, readErr := res.Body.Read(buf)
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				return err
+			}
+			h.Write(buf[:n])
+			result.Bytes += int64(n)
+			if progress != nil {
+				progress(result.Bytes, *total)
+			}
+		}
+		if readErr == io.EOF {
+			if *total >= 0 && result.Bytes < *total {
+				return &interruptedError{err: io.ErrUnexpectedEOF}
+			}
+			return nil
+		}
+		if readErr != nil {
+			return &interruptedError{err: readErr}
+		}
+	}
+}
+
+// parseContentRange parses a Content-Range header such as "bytes 100-199/1000"
+// or "bytes */1000" and returns the first byte and the complete size.
+func parseContentRange(header string) (start, size int64, ok bool) {
+	spec, found := strings.CutPrefix(header, "bytes ")
+	if !found {
+		return 0, 0, false
+	}
+	byteRange, sizeStr, found := strings.Cut(spec, "/")
+	if !found {
+		return 0, 0, false
+	}
+
+	size, err := strconv.ParseInt(sizeStr, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	if byteRange == "*" {
+		return 0, size, true
+	}
+
+	startStr, _, found := strings.Cut(byteRange, "-")
+	if !found {
+		return 0, 0, false
+	}
+	start, err = strconv.ParseInt(startStr, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return start, size, true
+}
@@ -0,0 +1,177 @@
+package together
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDownloadFineTune(t *testing.T) {
+	artifact := bytes.Repeat([]byte("0123456789abcdef"), 8<<10)
+	sum := sha256.Sum256(artifact)
+	checksum := hex.EncodeToString(sum[:])
+
+	// The server optionally drops the connection halfway through a full
+	// download, and optionally ignores Range requests.
+	var mu sync.Mutex
+	var interrupt, ignoreRange bool
+	var ranges []string
+	var step string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.URL.Path != "/v1/finetune/download" || r.URL.Query().Get("ft_id") != "ft-1" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"message":"fine-tune job not found"}}`))
+			return
+		}
+		ranges = append(ranges, r.Header.Get("Range"))
+		step = r.URL.Query().Get("checkpoint_step")
+
+		w.Header().Set("Content-Disposition", `attachment; filename="model.tar.zst"`)
+		w.Header().Set("Content-Type", "application/octet-stream")
+		if interrupt && r.Header.Get("Range") == "" {
+			interrupt = false
+			w.Header().Set("Content-Length", strconv.Itoa(len(artifact)))
+			w.Write(artifact[:len(artifact)/2])
+			return
+		}
+		if ignoreRange {
+			r.Header.Del("Range")
+		}
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(artifact))
+	}))
+	defer ts.Close()
+
+	reset := func(interruptDownload, ignoreRangeRequests bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		interrupt, ignoreRange, ranges = interruptDownload, ignoreRangeRequests, nil
+	}
+
+	req, _ := New("hunter2", WithBaseURL(ts.URL), WithRetryMax(0))
+
+	// Case: DownloadFineTune Fails with no id
+	if _, err := req.DownloadFineTune(context.TODO(), "", &bytes.Buffer{}, DownloadFineTuneOptions{}); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no fine-tune id provided")
+	}
+
+	// Case: Interrupted downloads are resumed with a Range request
+	reset(true, false)
+	var buf bytes.Buffer
+	var written, total int64
+	result, err := req.DownloadFineTune(context.TODO(), "ft-1", &buf, DownloadFineTuneOptions{
+		CheckpointStep: 10,
+		SHA256:         checksum,
+		Progress:       func(n, size int64) { written, total = n, size },
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := DownloadResult{Filename: "model.tar.zst", Bytes: int64(len(artifact)), SHA256: checksum}
+	if result != want || !bytes.Equal(buf.Bytes(), artifact) {
+		t.Errorf("Result was incorrect, got: %+v, want: %+v.", result, want)
+	}
+	if len(ranges) != 2 || ranges[1] != "bytes="+strconv.Itoa(len(artifact)/2)+"-" || step != "10" {
+		t.Errorf("Result was incorrect, got: ranges %q and step %q.", ranges, step)
+	}
+	if written != int64(len(artifact)) || total != int64(len(artifact)) {
+		t.Errorf("Result was incorrect, got: %d of %d, want: %d.", written, total, len(artifact))
+	}
+
+	// Case: Servers ignoring the Range header resend data that is skipped
+	reset(true, true)
+	buf.Reset()
+	result, err = req.DownloadFineTune(context.TODO(), "ft-1", &buf, DownloadFineTuneOptions{SHA256: checksum})
+	if err != nil || !bytes.Equal(buf.Bytes(), artifact) {
+		t.Errorf("Result was incorrect, got: %d bytes, %v, want: %d bytes.", buf.Len(), err, len(artifact))
+	}
+
+	// Case: A negative retry count disables resuming
+	reset(true, false)
+	_, err = req.DownloadFineTune(context.TODO(), "ft-1", &bytes.Buffer{}, DownloadFineTuneOptions{Retries: -1})
+	mu.Lock()
+	attempts := len(ranges)
+	mu.Unlock()
+	if err == nil || attempts != 1 {
+		t.Errorf("Result was incorrect, got: %d attempts, %v, want: %d attempt and an error.", attempts, err, 1)
+	}
+
+	// Case: Checksum mismatches are reported
+	reset(false, false)
+	_, err = req.DownloadFineTune(context.TODO(), "ft-1", &bytes.Buffer{}, DownloadFineTuneOptions{SHA256: "00"})
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Error was incorrect, got: %v, want: %v.", err, ErrChecksumMismatch)
+	}
+
+	// Case: API errors are returned
+	_, err = req.DownloadFineTune(context.TODO(), "ft-2", &bytes.Buffer{}, DownloadFineTuneOptions{})
+	if !IsNotFound(err) {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "fine-tune job not found")
+	}
+
+	// Case: Files resume from a partial download
+	path := filepath.Join(t.TempDir(), "model.tar.zst")
+	os.WriteFile(path+".part", artifact[:1000], 0o644)
+	reset(false, false)
+	result, err = req.DownloadFineTuneFile(context.TODO(), "ft-1", path, DownloadFineTuneOptions{SHA256: checksum})
+	if err != nil || result != want {
+		t.Errorf("Result was incorrect, got: %+v, %v, want: %+v.", result, err, want)
+	}
+	if got, _ := os.ReadFile(path); !bytes.Equal(got, artifact) {
+		t.Errorf("Result was incorrect, got: %d bytes, want: %d bytes.", len(got), len(artifact))
+	}
+	if _, err := os.Stat(path + ".part"); !os.IsNotExist(err) {
+		t.Errorf("Result was incorrect, got: %v, want: %s.", err, "partial file removed")
+	}
+	if len(ranges) != 1 || ranges[0] != "bytes=1000-" {
+		t.Errorf("Result was incorrect, got: %q, want: %q.", ranges, "bytes=1000-")
+	}
+
+	// Case: Complete partial files are only verified
+	os.WriteFile(path+".part", artifact, 0o644)
+	result, err = req.DownloadFineTuneFile(context.TODO(), "ft-1", path, DownloadFineTuneOptions{SHA256: checksum})
+	if err != nil || result.SHA256 != checksum {
+		t.Errorf("Result was incorrect, got: %+v, %v.", result, err)
+	}
+
+	// Case: Corrupt partial files are removed
+	os.WriteFile(path+".part", []byte("corrupt"), 0o644)
+	_, err = req.DownloadFineTuneFile(context.TODO(), "ft-1", path, DownloadFineTuneOptions{SHA256: checksum})
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Error was incorrect, got: %v, want: %v.", err, ErrChecksumMismatch)
+	}
+	if _, err := os.Stat(path + ".part"); !os.IsNotExist(err) {
+		t.Errorf("Result was incorrect, got: %v, want: %s.", err, "partial file removed")
+	}
+}
+
+func TestParseContentRange(t *testing.T) {
+	tests := map[string]struct {
+		start, size int64
+		ok          bool
+	}{
+		"bytes 100-199/1000": {100, 1000, true},
+		"bytes */1000":       {0, 1000, true},
+		"bytes 100-199/*":    {0, 0, false},
+		"items 0-1/2":        {0, 0, false},
+		"":                   {0, 0, false},
+	}
+	for header, want := range tests {
+		start, size, ok := parseContentRange(header)
+		if start != want.start || size != want.size || ok != want.ok {
+			t.Errorf("Result was incorrect for %q, got: %d %d %v, want: %d %d %v.", header, start, size, ok, want.start, want.size, want.ok)
+		}
+	}
+}
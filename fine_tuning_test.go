@@ -1,7 +1,6 @@
 package together
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -11,249 +10,118 @@ import (
 	"testing"
 )
 
-func TestListRunningInstances(t *testing.T) {
+func TestFineTuneStatus(t *testing.T) {
+	terminal := map[FineTuneStatus]bool{
+		FineTuneStatusPending:         false,
+		FineTuneStatusQueued:          false,
+		FineTuneStatusRunning:         false,
+		FineTuneStatusCompressing:     false,
+		FineTuneStatusUploading:       false,
+		FineTuneStatusCancelRequested: false,
+		FineTuneStatusCancelled:       true,
+		FineTuneStatusError:           true,
+		FineTuneStatusCompleted:       true,
+	}
+	for status, want := range terminal {
+		if got := status.Terminal(); got != want {
+			t.Errorf("Result was incorrect for %s, got: %v, want: %v.", status, got, want)
+		}
+	}
+}
+
+func TestFineTunes(t *testing.T) {
 	req, _ := New("hunter2")
 	req.Client.RetryMax = 1
 	req.BaseURL = ""
-	req.Debug = false
-
-	// ListRunningInstances
-	//
-	// Case: ListRunningInstances Fails with no context
-	resp, err := req.ListRunningInstances(nil) //lint:ignore SA1012 nil context used intentionally
-	if !reflect.DeepEqual(resp, FineTuningResponse{}) {
-		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, FineTuningResponse{})
-	}
-	if err == nil {
-		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "no context provided")
-	}
-
-	// Case: ListRunningInstances Fails with invalid HTTP request
-	resp, err = req.ListRunningInstances(context.TODO())
-	if !reflect.DeepEqual(resp, FineTuningResponse{}) {
-		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, FineTuningResponse{})
-	}
-	if err == nil {
-		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "!nil")
-	}
-
-	// Case: ListRunningInstances Fails with HTTP 200 and malformed body
-	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprintln(w, "Hello, client")
-	}))
-
-	req.BaseURL = ts.URL
-
-	resp, err = req.ListRunningInstances(context.TODO())
-	if !reflect.DeepEqual(resp, FineTuningResponse{}) {
-		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, FineTuningResponse{})
-	}
-	if err == nil {
-		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "!nil")
-	}
-
-	ts.Close()
-
-	// Case: ListRunningInstances Fails with HTTP 400
-	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(400)
-		fmt.Fprintln(w, nil)
-	}))
-
-	req.BaseURL = ts.URL
-
-	resp, err = req.ListRunningInstances(context.TODO())
-	if !reflect.DeepEqual(resp, FineTuningResponse{}) {
-		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, FineTuningResponse{})
-	}
-	if err == nil {
-		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "!nil")
-	}
-
-	ts.Close()
-
-	// Case: ListRunningInstances Succeeds with HTTP 200
-	req.Debug = true
-	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		resp, _ := json.Marshal(EmbeddingsResponse{})
-		fmt.Fprintln(w, bytes.NewBuffer(resp))
-	}))
-
-	req.BaseURL = ts.URL
-
-	resp, err = req.ListRunningInstances(context.TODO())
-	if !reflect.DeepEqual(resp, FineTuningResponse{}) {
-		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, FineTuningResponse{})
-	}
-	if err != nil {
-		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "!nil")
-	}
-
-	ts.Close()
-
-	// StartFineTunedInstance
-	//
-	// Case: StartFineTunedInstance Fails with no context
-	resp, err = req.StartFineTunedInstance(nil, "") //lint:ignore SA1012 nil context used intentionally
-	if !reflect.DeepEqual(resp, FineTuningResponse{}) {
-		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, FineTuningResponse{})
-	}
-	if err == nil {
-		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "no context provided")
-	}
 
-	// Case: StartFineTunedInstance Fails with no name
-	resp, err = req.StartFineTunedInstance(context.TODO(), "")
-	if !reflect.DeepEqual(resp, FineTuningResponse{}) {
-		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, FineTuningResponse{})
-	}
-	if err == nil {
-		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "no name provided")
+	// Case: CreateFineTune Fails with no context
+	if _, err := req.CreateFineTune(nil, "m", "file-1", FineTuneRequest{}); err == nil { //lint:ignore SA1012 nil context used intentionally
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no context provided")
 	}
 
-	// Case: StartFineTunedInstance Fails with invalid HTTP request
-	resp, err = req.StartFineTunedInstance(context.TODO(), "a")
-	if !reflect.DeepEqual(resp, FineTuningResponse{}) {
-		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, FineTuningResponse{})
+	// Case: CreateFineTune Fails with no model or training file
+	if _, err := req.CreateFineTune(context.TODO(), "", "file-1", FineTuneRequest{}); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no model provided")
 	}
-	if err == nil {
-		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "!nil")
+	if _, err := req.CreateFineTune(context.TODO(), "m", "", FineTuneRequest{}); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no training file provided")
 	}
 
-	// Case: StartFineTunedInstance Fails with HTTP 200 and malformed body
-	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprintln(w, "Hello, client")
-	}))
-
-	req.BaseURL = ts.URL
-
-	resp, err = req.StartFineTunedInstance(context.TODO(), "a")
-	if !reflect.DeepEqual(resp, FineTuningResponse{}) {
-		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, FineTuningResponse{})
+	// Case: RetrieveFineTune and CancelFineTune Fail with no id
+	if _, err := req.RetrieveFineTune(context.TODO(), ""); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no fine-tune id provided")
 	}
-	if err == nil {
-		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "!nil")
+	if _, err := req.CancelFineTune(context.TODO(), ""); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no fine-tune id provided")
 	}
 
-	ts.Close()
-
-	// Case: StartFineTunedInstance Fails with HTTP 400
-	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(400)
-		fmt.Fprintln(w, nil)
-	}))
-
-	req.BaseURL = ts.URL
-
-	resp, err = req.StartFineTunedInstance(context.TODO(), "a")
-	if !reflect.DeepEqual(resp, FineTuningResponse{}) {
-		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, FineTuningResponse{})
-	}
-	if err == nil {
-		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "!nil")
-	}
-
-	ts.Close()
-
-	// Case: StartFineTunedInstance Succeeds with HTTP 200
-	req.Debug = true
-	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		resp, _ := json.Marshal(EmbeddingsResponse{})
-		fmt.Fprintln(w, bytes.NewBuffer(resp))
+	var created map[string]any
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /v1/fine-tunes":
+			_ = json.NewDecoder(r.Body).Decode(&created)
+			fmt.Fprintln(w, `{"id":"ft-1","status":"pending","model":"m","training_file":"file-1","n_epochs":3,"lora":true,"lora_r":8}`)
+		case "GET /v1/fine-tunes":
+			fmt.Fprintln(w, `{"data":[{"id":"ft-1","status":"running","epochs_completed":1},{"id":"ft-2","status":"completed","output_name":"user/m-ft"}]}`)
+		case "GET /v1/fine-tunes/ft-1":
+			fmt.Fprintln(w, `{"id":"ft-1","status":"running","created_at":"2024-05-01T12:00:00.000Z"}`)
+		case "GET /v1/fine-tunes/ft-1/checkpoints":
+			fmt.Fprintln(w, `{"data":[{"step":10,"path":"s3://bucket/ft-1/10","checkpoint_type":"intermediate"}]}`)
+		case "POST /v1/fine-tunes/ft-1/cancel":
+			fmt.Fprintln(w, `{"id":"ft-1","status":"cancel_requested"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintln(w, `{"error":{"message":"fine-tune job not found"}}`)
+		}
 	}))
+	defer ts.Close()
 
 	req.BaseURL = ts.URL
 
-	resp, err = req.StartFineTunedInstance(context.TODO(), "a")
-	if !reflect.DeepEqual(resp, FineTuningResponse{}) {
-		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, FineTuningResponse{})
-	}
+	// Case: CreateFineTune sends the typed request
+	job, err := req.CreateFineTune(context.TODO(), "m", "file-1", FineTuneRequest{NEpochs: 3, Lora: true, LoraR: 8, Suffix: "test"})
 	if err != nil {
-		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "!nil")
+		t.Fatalf("Unexpected error: %v", err)
 	}
-
-	ts.Close()
-
-	// StopFineTunedInstance
-	//
-	// Case: StopFineTunedInstance Fails with no context
-	resp, err = req.StopFineTunedInstance(nil, "") //lint:ignore SA1012 nil context used intentionally
-	if !reflect.DeepEqual(resp, FineTuningResponse{}) {
-		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, FineTuningResponse{})
+	want := FineTuneJob{Id: "ft-1", Status: FineTuneStatusPending, Model: "m", TrainingFile: "file-1", NEpochs: 3, Lora: true, LoraR: 8}
+	if !reflect.DeepEqual(job, want) {
+		t.Errorf("Result was incorrect, got: %+v, want: %+v.", job, want)
 	}
-	if err == nil {
-		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "no context provided")
+	wantBody := map[string]any{"model": "m", "training_file": "file-1", "n_epochs": float64(3), "lora": true, "lora_r": float64(8), "suffix": "test"}
+	if !reflect.DeepEqual(created, wantBody) {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", created, wantBody)
 	}
 
-	// Case: StopFineTunedInstance Fails with no name
-	resp, err = req.StopFineTunedInstance(context.TODO(), "")
-	if !reflect.DeepEqual(resp, FineTuningResponse{}) {
-		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, FineTuningResponse{})
-	}
-	if err == nil {
-		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "no name provided")
+	// Case: ListFineTunes
+	list, err := req.ListFineTunes(context.TODO())
+	if err != nil || len(list.Data) != 2 || list.Data[0].EpochsCompleted != 1 || list.Data[1].OutputName != "user/m-ft" {
+		t.Errorf("Result was incorrect, got: %+v, %v.", list, err)
 	}
 
-	// Case: StopFineTunedInstance Fails with invalid HTTP request
-	resp, err = req.StopFineTunedInstance(context.TODO(), "a")
-	if !reflect.DeepEqual(resp, FineTuningResponse{}) {
-		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, FineTuningResponse{})
+	// Case: RetrieveFineTune
+	job, err = req.RetrieveFineTune(context.TODO(), "ft-1")
+	if err != nil || job.Status != FineTuneStatusRunning || job.CreatedAt != "2024-05-01T12:00:00.000Z" {
+		t.Errorf("Result was incorrect, got: %+v, %v.", job, err)
 	}
-	if err == nil {
-		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "!nil")
-	}
-
-	// Case: StopFineTunedInstance Fails with HTTP 200 and malformed body
-	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprintln(w, "Hello, client")
-	}))
-
-	req.BaseURL = ts.URL
 
-	resp, err = req.StopFineTunedInstance(context.TODO(), "a")
-	if !reflect.DeepEqual(resp, FineTuningResponse{}) {
-		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, FineTuningResponse{})
+	// Case: RetrieveFineTune Fails with HTTP 404
+	if _, err := req.RetrieveFineTune(context.TODO(), "ft-2"); !IsNotFound(err) {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "fine-tune job not found")
 	}
-	if err == nil {
-		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "!nil")
-	}
-
-	ts.Close()
 
-	// Case: StopFineTunedInstance Fails with HTTP 400
-	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(400)
-		fmt.Fprintln(w, nil)
-	}))
-
-	req.BaseURL = ts.URL
-
-	resp, err = req.StopFineTunedInstance(context.TODO(), "a")
-	if !reflect.DeepEqual(resp, FineTuningResponse{}) {
-		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, FineTuningResponse{})
+	// Case: ListCheckpoints
+	checkpoints, err := req.ListCheckpoints(context.TODO(), "ft-1")
+	wantCheckpoint := FineTuneCheckpoint{Step: 10, Path: "s3://bucket/ft-1/10", CheckpointType: "intermediate"}
+	if err != nil || len(checkpoints.Data) != 1 || checkpoints.Data[0] != wantCheckpoint {
+		t.Errorf("Result was incorrect, got: %+v, %v, want: %+v.", checkpoints, err, wantCheckpoint)
 	}
-	if err == nil {
-		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "!nil")
+	if _, err := req.ListCheckpoints(context.TODO(), ""); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no fine-tune id provided")
 	}
 
-	ts.Close()
-
-	// Case: StopFineTunedInstance Succeeds with HTTP 200
-	req.Debug = true
-	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		resp, _ := json.Marshal(EmbeddingsResponse{})
-		fmt.Fprintln(w, bytes.NewBuffer(resp))
-	}))
-
-	req.BaseURL = ts.URL
-
-	resp, err = req.StopFineTunedInstance(context.TODO(), "a")
-	if !reflect.DeepEqual(resp, FineTuningResponse{}) {
-		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, FineTuningResponse{})
-	}
-	if err != nil {
-		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "!nil")
+	// Case: CancelFineTune
+	job, err = req.CancelFineTune(context.TODO(), "ft-1")
+	if err != nil || job.Status != FineTuneStatusCancelRequested {
+		t.Errorf("Result was incorrect, got: %+v, %v.", job, err)
 	}
-
-	ts.Close()
 }
@@ -0,0 +1,127 @@
+package together
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultPollInterval    = 5 * time.Second
+	defaultMaxPollInterval = time.Minute
+)
+
+type WaitForFineTuneOptions struct {
+	PollInterval    time.Duration       // Initial delay between polls; defaults to 5 seconds.
+	MaxPollInterval time.Duration       // Upper bound of the growing delay; defaults to 1 minute.
+	OnEvent         func(FineTuneEvent) // Called once for every new event, in order.
+}
+
+// FineTuneError is returned by WaitForFineTune when a job ends without
+// completing, either because it failed or because it was cancelled.
+type FineTuneError struct {
+	Job     FineTuneJob
+	Message string // Message of the last error event, if any.
+}
+
+func (e *FineTuneError) Error() string {
+	if e.Job.Status == FineTuneStatusCancelled {
+		return fmt.Sprintf("fine-tune %s was cancelled", e.Job.Id)
+	}
+	if e.Message != "" {
+		return fmt.Sprintf("fine-tune %s failed: %s", e.Job.Id, e.Message)
+	}
+	return fmt.Sprintf("fine-tune %s failed with status %s", e.Job.Id, e.Job.Status)
+}
+
+// WaitForFineTune polls a fine-tuning job until it reaches a terminal status
+// and returns the final job. Polling backs off while nothing changes and
+// speeds up again when new events appear.
+//
+// If the job fails or is cancelled, the job is returned together with a
+// *FineTuneError.
+func (api *API) WaitForFineTune(ctx context.Context, id string, opts WaitForFineTuneOptions) (FineTuneJob, error) {
+	if ctx == nil {
+		return FineTuneJob{}, fmt.Errorf("no context provided")
+	}
+	if id == "" {
+		return FineTuneJob{}, fmt.Errorf("no fine-tune id provided")
+	}
+
+	var job FineTuneJob
+	var lastError string
+	seen := 0
+
+	err := poll(ctx, opts.PollInterval, opts.MaxPollInterval, func() (bool, bool, error) {
+		var err error
+		job, err = api.RetrieveFineTune(ctx, id)
+		if err != nil {
+			return false, false, err
+		}
+
+		// Events are fetched after the job so that the final events of a
+		// finished job are not missed.
+		events, err := api.ListFineTuneEvents(ctx, id)
+		if err != nil {
+			return false, false, err
+		}
+
+		// The endpoint returns the full history, so events past the ones
+		// already seen are new.
+		fresh := events.Data[min(seen, len(events.Data)):]
+		seen = max(seen, len(events.Data))
+		for _, event := range fresh {
+			if event.Type == FineTuneEventJobError || event.Level == "error" {
+				lastError = event.Message
+			}
+			if opts.OnEvent != nil {
+				opts.OnEvent(event)
+			}
+		}
+
+		return job.Status.Terminal(), len(fresh) > 0, nil
+	})
+	if err != nil {
+		return job, err
+	}
+
+	if job.Status != FineTuneStatusCompleted {
+		return job, &FineTuneError{Job: job, Message: lastError}
+	}
+	return job, nil
+}
+
+// poll calls check until it reports done, sleeping between calls. The delay
+// starts at interval and grows up to maxInterval, and is reset whenever check
+// reports progress.
+func poll(ctx context.Context, interval, maxInterval time.Duration, check func() (done, progress bool, err error)) error {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	if maxInterval <= 0 {
+		maxInterval = defaultMaxPollInterval
+	}
+	maxInterval = max(maxInterval, interval)
+
+	delay := interval
+	for {
+		done, progress, err := check()
+		if err != nil || done {
+			return err
+		}
+
+		if progress {
+			delay = interval
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		delay = min(delay*3/2, maxInterval)
+	}
+}
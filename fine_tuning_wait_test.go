@@ -0,0 +1,112 @@
+package together
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWaitForFineTune(t *testing.T) {
+	// The server advances the job by one step on every poll, adding an event
+	// each time, until it reaches the final status.
+	var mu sync.Mutex
+	var polls int
+	var final FineTuneStatus
+	events := []FineTuneEvent{
+		{Type: FineTuneEventTrainingStart, Message: "training started"},
+		{Type: FineTuneEventCheckpointSave, Message: "checkpoint saved", Step: 10},
+		{Type: FineTuneEventJobError, Level: "error", Message: "out of memory"},
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.URL.Path {
+		case "/v1/fine-tunes/ft-1":
+			polls++
+			status := FineTuneStatusRunning
+			if polls >= 3 {
+				status = final
+			}
+			json.NewEncoder(w).Encode(FineTuneJob{Id: "ft-1", Status: status})
+		case "/v1/fine-tunes/ft-1/events":
+			n := max(0, min(polls, len(events)))
+			if final != FineTuneStatusError {
+				n = min(n, 2)
+			}
+			json.NewEncoder(w).Encode(FineTuneEventsResponse{Data: events[:n]})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	reset := func(status FineTuneStatus) {
+		mu.Lock()
+		defer mu.Unlock()
+		polls, final = 0, status
+	}
+
+	req, _ := New("hunter2", WithBaseURL(ts.URL), WithRetryMax(0))
+	opts := WaitForFineTuneOptions{PollInterval: time.Millisecond, MaxPollInterval: 2 * time.Millisecond}
+
+	// Case: WaitForFineTune Fails with no id
+	if _, err := req.WaitForFineTune(context.TODO(), "", opts); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no fine-tune id provided")
+	}
+
+	// Case: Completed jobs are returned and every event is emitted once
+	reset(FineTuneStatusCompleted)
+	var emitted []FineTuneEvent
+	opts.OnEvent = func(event FineTuneEvent) { emitted = append(emitted, event) }
+	job, err := req.WaitForFineTune(context.TODO(), "ft-1", opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if job.Status != FineTuneStatusCompleted || polls != 3 {
+		t.Errorf("Result was incorrect, got: %s after %d polls, want: %s after %d polls.", job.Status, polls, FineTuneStatusCompleted, 3)
+	}
+	if !reflect.DeepEqual(emitted, events[:2]) {
+		t.Errorf("Result was incorrect, got: %+v, want: %+v.", emitted, events[:2])
+	}
+
+	// Case: Failed jobs return a FineTuneError with the error event message
+	reset(FineTuneStatusError)
+	opts.OnEvent = nil
+	job, err = req.WaitForFineTune(context.TODO(), "ft-1", opts)
+	var fineTuneErr *FineTuneError
+	if !errors.As(err, &fineTuneErr) || fineTuneErr.Message != "out of memory" || job.Status != FineTuneStatusError {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "fine-tune ft-1 failed: out of memory")
+	}
+
+	// Case: Cancelled jobs return a FineTuneError
+	reset(FineTuneStatusCancelled)
+	_, err = req.WaitForFineTune(context.TODO(), "ft-1", opts)
+	if !errors.As(err, &fineTuneErr) || err.Error() != "fine-tune ft-1 was cancelled" {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "fine-tune ft-1 was cancelled")
+	}
+
+	// Case: Waiting stops when the context is done
+	reset(FineTuneStatusCompleted)
+	mu.Lock()
+	polls = -1000
+	mu.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = req.WaitForFineTune(ctx, "ft-1", opts)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Error was incorrect, got: %v, want: %v.", err, context.DeadlineExceeded)
+	}
+
+	// Case: API errors are returned
+	_, err = req.WaitForFineTune(context.TODO(), "ft-2", opts)
+	if !IsNotFound(err) {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "404 Not Found")
+	}
+}
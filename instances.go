@@ -0,0 +1,252 @@
+package together
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+type InstanceState string
+
+const (
+	InstanceStateStarting InstanceState = "starting"
+	InstanceStateReady    InstanceState = "ready"
+	InstanceStateStopping InstanceState = "stopping"
+	InstanceStateStopped  InstanceState = "stopped"
+	InstanceStateFailed   InstanceState = "failed"
+)
+
+// Instance is a dedicated instance of a model.
+//
+// The /instances endpoints may report an instance as a plain boolean that only
+// says whether it is running. Such instances are decoded as ready or stopped.
+type Instance struct {
+	Model         string        `json:"model"`
+	State         InstanceState `json:"state"`
+	Hardware      string        `json:"hardware"`
+	Replicas      int           `json:"replicas"`       // Requested number of replicas.
+	ReadyReplicas int           `json:"ready_replicas"` // Replicas that are ready to serve requests.
+}
+
+// Ready reports whether the instance can serve requests. Ready replicas are
+// only taken into account if no state was reported.
+func (i Instance) Ready() bool {
+	if i.State == "" {
+		return i.ReadyReplicas > 0
+	}
+	return i.State == InstanceStateReady
+}
+
+// UnmarshalJSON accepts an instance object or a boolean running state. The
+// state is also read from a "status" key and normalized to lower case.
+func (i *Instance) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return fmt.Errorf("instance is null")
+	}
+
+	var running bool
+	if err := json.Unmarshal(data, &running); err == nil {
+		i.State = InstanceStateStopped
+		if running {
+			i.State = InstanceStateReady
+		}
+		return nil
+	}
+
+	type instance Instance
+	raw := struct {
+		*instance
+		Status InstanceState `json:"status"`
+	}{instance: (*instance)(i)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if i.State == "" {
+		i.State = raw.Status
+	}
+	i.State = InstanceState(strings.ToLower(string(i.State)))
+	return nil
+}
+
+// InstanceList is a list of instances sorted by model name.
+type InstanceList []Instance
+
+// Get returns the instance of the given model.
+func (l InstanceList) Get(model string) (Instance, bool) {
+	for _, instance := range l {
+		if instance.Model == model {
+			return instance, true
+		}
+	}
+	return Instance{}, false
+}
+
+// UnmarshalJSON accepts either an array of instances or an object mapping
+// model names to instances.
+func (l *InstanceList) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var instances []Instance
+		if err := json.Unmarshal(data, &instances); err != nil {
+			return err
+		}
+		*l = instances
+	} else {
+		var instances map[string]Instance
+		if err := json.Unmarshal(data, &instances); err != nil {
+			return err
+		}
+		*l = make(InstanceList, 0, len(instances))
+		for model, instance := range instances {
+			if instance.Model == "" {
+				instance.Model = model
+			}
+			*l = append(*l, instance)
+		}
+	}
+
+	sort.Slice(*l, func(i, j int) bool { return (*l)[i].Model < (*l)[j].Model })
+	return nil
+}
+
+type WaitForInstanceOptions struct {
+	PollInterval    time.Duration // Initial delay between polls; defaults to 5 seconds.
+	MaxPollInterval time.Duration // Upper bound of the growing delay; defaults to 1 minute.
+}
+
+// List Running Instances is the endpoint for listing running fine-tuning instances.
+//
+// API Reference: https://docs.together.ai/reference/instances
+func (api *API) ListRunningInstances(ctx context.Context) (InstanceList, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("no context provided")
+	}
+
+	uri := defaultBasePath + "instances"
+
+	res, err := api.request(ctx, "GET", uri, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, newAPIError(res, body)
+	}
+
+	var instanceList InstanceList
+	err = json.Unmarshal(body, &instanceList)
+	if err != nil {
+		return nil, err
+	}
+
+	return instanceList, nil
+}
+
+// Start Fine-tuned Instance is the endpoint for starting a fine-tuned model.
+// Use WaitForInstance to wait until the instance is ready.
+//
+// The /instances routes are legacy; new deployments are managed as dedicated
+// endpoints, see CreateEndpoint.
+//
+// API Reference: https://docs.together.ai/reference/instances-start
+func (api *API) StartFineTunedInstance(ctx context.Context, name string) (Instance, error) {
+	return api.instanceAction(ctx, "start", name)
+}
+
+// Stop Fine-tuned Instance is the endpoint for stopping a fine-tuned model.
+//
+// API Reference: https://docs.together.ai/reference/instances-stop
+func (api *API) StopFineTunedInstance(ctx context.Context, name string) (Instance, error) {
+	return api.instanceAction(ctx, "stop", name)
+}
+
+// instanceAction starts or stops an instance and returns its new state.
+func (api *API) instanceAction(ctx context.Context, action, name string) (Instance, error) {
+	if ctx == nil {
+		return Instance{}, fmt.Errorf("no context provided")
+	}
+	if name == "" {
+		return Instance{}, fmt.Errorf("no name provided")
+	}
+
+	uri := defaultBasePath + "instances/" + action + "?model=" + url.QueryEscape(name)
+
+	res, err := api.request(ctx, "POST", uri, nil, nil)
+	if err != nil {
+		return Instance{}, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return Instance{}, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return Instance{}, newAPIError(res, body)
+	}
+
+	// The response is either the instance itself or a map of instances.
+	var instance Instance
+	err = json.Unmarshal(body, &instance)
+	if err != nil {
+		return Instance{}, err
+	}
+	if instance.Model == "" {
+		var instanceList InstanceList
+		if json.Unmarshal(body, &instanceList) == nil {
+			if listed, ok := instanceList.Get(name); ok {
+				instance = listed
+			}
+		}
+		instance.Model = name
+	}
+
+	return instance, nil
+}
+
+// WaitForInstance polls the running instances until the instance of the given
+// model is ready to serve requests, and returns it.
+func (api *API) WaitForInstance(ctx context.Context, model string, opts WaitForInstanceOptions) (Instance, error) {
+	if ctx == nil {
+		return Instance{}, fmt.Errorf("no context provided")
+	}
+	if model == "" {
+		return Instance{}, fmt.Errorf("no model provided")
+	}
+
+	var instance Instance
+	var state InstanceState
+	err := poll(ctx, opts.PollInterval, opts.MaxPollInterval, func() (bool, bool, error) {
+		instances, err := api.ListRunningInstances(ctx)
+		if err != nil {
+			return false, false, err
+		}
+
+		var ok bool
+		instance, ok = instances.Get(model)
+		if ok && instance.State == InstanceStateFailed {
+			return false, false, fmt.Errorf("instance of %s failed", model)
+		}
+
+		progress := instance.State != state
+		state = instance.State
+		return ok && instance.Ready(), progress, nil
+	})
+	if err != nil {
+		return instance, err
+	}
+
+	return instance, nil
+}
@@ -0,0 +1,242 @@
+package together
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestListRunningInstances(t *testing.T) {
+	req, _ := New("hunter2")
+	req.Client.RetryMax = 1
+	req.BaseURL = ""
+	req.Debug = false
+
+	// Case: ListRunningInstances Fails with no context
+	resp, err := req.ListRunningInstances(nil) //lint:ignore SA1012 nil context used intentionally
+	if resp != nil {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, nil)
+	}
+	if err == nil {
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "no context provided")
+	}
+
+	// Case: ListRunningInstances Fails with invalid HTTP request
+	resp, err = req.ListRunningInstances(context.TODO())
+	if resp != nil {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, nil)
+	}
+	if err == nil {
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "!nil")
+	}
+
+	// Case: ListRunningInstances Fails with HTTP 200 and malformed body
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "Hello, client")
+	}))
+
+	req.BaseURL = ts.URL
+
+	resp, err = req.ListRunningInstances(context.TODO())
+	if resp != nil {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, nil)
+	}
+	if err == nil {
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "!nil")
+	}
+
+	ts.Close()
+
+	// Case: ListRunningInstances Fails with HTTP 400
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(400)
+		fmt.Fprintln(w, nil)
+	}))
+
+	req.BaseURL = ts.URL
+
+	resp, err = req.ListRunningInstances(context.TODO())
+	if resp != nil {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, nil)
+	}
+	if err == nil {
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "!nil")
+	}
+
+	ts.Close()
+
+	// Case: ListRunningInstances Succeeds with a map of running states
+	req.Debug = true
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"user/b":false,"user/a":true,"user/c":{"status":"STARTING","hardware":"1x_nvidia_h100_80gb_sxm","replicas":2,"ready_replicas":0}}`)
+	}))
+
+	req.BaseURL = ts.URL
+
+	resp, err = req.ListRunningInstances(context.TODO())
+	want := InstanceList{
+		{Model: "user/a", State: InstanceStateReady},
+		{Model: "user/b", State: InstanceStateStopped},
+		{Model: "user/c", State: InstanceStateStarting, Hardware: "1x_nvidia_h100_80gb_sxm", Replicas: 2},
+	}
+	if !reflect.DeepEqual(resp, want) {
+		t.Errorf("Result was incorrect, got: %+v, want: %+v.", resp, want)
+	}
+	if err != nil {
+		t.Errorf("Error was incorrect, got: %s, want: %v.", err, nil)
+	}
+	if instance, ok := resp.Get("user/c"); !ok || instance.Ready() {
+		t.Errorf("Result was incorrect, got: %+v, want: %s.", instance, "not ready")
+	}
+
+	ts.Close()
+
+	// Case: ListRunningInstances Succeeds with a list of instances
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `[{"model":"user/z","state":"ready","ready_replicas":1},{"model":"user/y","state":"stopping"}]`)
+	}))
+
+	req.BaseURL = ts.URL
+
+	resp, _ = req.ListRunningInstances(context.TODO())
+	want = InstanceList{
+		{Model: "user/y", State: InstanceStateStopping},
+		{Model: "user/z", State: InstanceStateReady, ReadyReplicas: 1},
+	}
+	if !reflect.DeepEqual(resp, want) {
+		t.Errorf("Result was incorrect, got: %+v, want: %+v.", resp, want)
+	}
+
+	ts.Close()
+
+	// Case: ListRunningInstances Fails with a null instance
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"user/a":null}`)
+	}))
+
+	req.BaseURL = ts.URL
+
+	if _, err := req.ListRunningInstances(context.TODO()); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "instance is null")
+	}
+
+	ts.Close()
+
+	// Case: Ready replicas only count if no state was reported
+	if (Instance{State: InstanceStateStopping, ReadyReplicas: 1}).Ready() {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", true, false)
+	}
+	if !(Instance{ReadyReplicas: 1}).Ready() {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", false, true)
+	}
+}
+
+func TestStartStopFineTunedInstance(t *testing.T) {
+	req, _ := New("hunter2")
+	req.Client.RetryMax = 1
+	req.BaseURL = ""
+
+	// Case: Fails with no context
+	if _, err := req.StartFineTunedInstance(nil, "a"); err == nil { //lint:ignore SA1012 nil context used intentionally
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "no context provided")
+	}
+	if _, err := req.StopFineTunedInstance(nil, "a"); err == nil { //lint:ignore SA1012 nil context used intentionally
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "no context provided")
+	}
+
+	// Case: Fails with no name
+	if _, err := req.StartFineTunedInstance(context.TODO(), ""); err == nil {
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "no name provided")
+	}
+	if _, err := req.StopFineTunedInstance(context.TODO(), ""); err == nil {
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "no name provided")
+	}
+
+	// Case: Fails with invalid HTTP request
+	if _, err := req.StartFineTunedInstance(context.TODO(), "a"); err == nil {
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "!nil")
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/instances/start":
+			fmt.Fprintf(w, `{"model":%q,"state":"starting","replicas":1}`, r.URL.Query().Get("model"))
+		case "/instances/stop":
+			fmt.Fprintf(w, `{%q:false}`, r.URL.Query().Get("model"))
+		}
+	}))
+	defer ts.Close()
+
+	req.BaseURL = ts.URL
+
+	// Case: StartFineTunedInstance decodes an instance
+	instance, err := req.StartFineTunedInstance(context.TODO(), "user/a")
+	if err != nil || !reflect.DeepEqual(instance, Instance{Model: "user/a", State: InstanceStateStarting, Replicas: 1}) {
+		t.Errorf("Result was incorrect, got: %+v, %v.", instance, err)
+	}
+
+	// Case: StopFineTunedInstance decodes a map of instances
+	instance, err = req.StopFineTunedInstance(context.TODO(), "user/a")
+	if err != nil || !reflect.DeepEqual(instance, Instance{Model: "user/a", State: InstanceStateStopped}) {
+		t.Errorf("Result was incorrect, got: %+v, %v.", instance, err)
+	}
+}
+
+func TestWaitForInstance(t *testing.T) {
+	// The server reports the instance as starting for the first two polls.
+	var mu sync.Mutex
+	var polls int
+	var final string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		polls++
+		state := "starting"
+		if polls > 2 {
+			state = final
+		}
+		fmt.Fprintf(w, `{"user/a":{"state":%q}}`, state)
+	}))
+	defer ts.Close()
+
+	reset := func(state string) {
+		mu.Lock()
+		defer mu.Unlock()
+		polls, final = 0, state
+	}
+
+	req, _ := New("hunter2", WithBaseURL(ts.URL), WithRetryMax(0))
+	opts := WaitForInstanceOptions{PollInterval: time.Millisecond, MaxPollInterval: 2 * time.Millisecond}
+
+	// Case: WaitForInstance Fails with no model
+	if _, err := req.WaitForInstance(context.TODO(), "", opts); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no model provided")
+	}
+
+	// Case: Returns once the instance is ready
+	reset("ready")
+	instance, err := req.WaitForInstance(context.TODO(), "user/a", opts)
+	if err != nil || instance.State != InstanceStateReady || polls != 3 {
+		t.Errorf("Result was incorrect, got: %+v after %d polls, %v.", instance, polls, err)
+	}
+
+	// Case: Failed instances return an error
+	reset("failed")
+	if _, err := req.WaitForInstance(context.TODO(), "user/a", opts); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "instance of user/a failed")
+	}
+
+	// Case: Waiting stops when the context is done
+	reset("starting")
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := req.WaitForInstance(ctx, "user/a", opts); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %v.", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,193 @@
+package together
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// charsPerToken is a rough average used to estimate token counts client side.
+const charsPerToken = 4
+
+// WithRateLimit enables a client-side limiter that blocks requests until they
+// fit within the given requests-per-second and tokens-per-minute budgets. A
+// zero value disables the corresponding budget.
+//
+// Token usage is estimated from the request and reconciled with the usage
+// reported in the response, except for streamed responses.
+func WithRateLimit(requestsPerSecond float64, tokensPerMinute int) Option {
+	return func(api *API) error {
+		if requestsPerSecond < 0 || tokensPerMinute < 0 {
+			return errors.New("invalid rate limit: must not be negative")
+		}
+
+		api.limiter = newLimiter(requestsPerSecond, tokensPerMinute)
+		return nil
+	}
+}
+
+type limiter struct {
+	requests *tokenBucket
+	tokens   *tokenBucket
+}
+
+func newLimiter(requestsPerSecond float64, tokensPerMinute int) *limiter {
+	l := &limiter{}
+	if requestsPerSecond > 0 {
+		l.requests = newTokenBucket(requestsPerSecond, max(requestsPerSecond, 1))
+	}
+	if tokensPerMinute > 0 {
+		l.tokens = newTokenBucket(float64(tokensPerMinute)/60, float64(tokensPerMinute))
+	}
+	return l
+}
+
+// wait blocks until a request estimated to use the given number of tokens is
+// allowed, or until ctx is done, in which case the reservation is released.
+func (l *limiter) wait(ctx context.Context, tokens int) error {
+	delay := l.requests.take(1)
+	delay = max(delay, l.tokens.take(float64(tokens)))
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		l.requests.refund(1)
+		l.tokens.refund(float64(tokens))
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reconcile corrects the token budget once the actual usage is known.
+func (l *limiter) reconcile(estimated, actual int) {
+	l.tokens.refund(float64(estimated - actual))
+}
+
+// reconcileResponse reconciles the token budget with the usage reported in a
+// JSON response. The body is read and replaced so the caller can still decode
+// it. Failed requests are assumed not to have consumed any tokens.
+func (l *limiter) reconcileResponse(resp *http.Response, estimated int) error {
+	if resp.StatusCode >= http.StatusBadRequest {
+		l.reconcile(estimated, 0)
+		return nil
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		return nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if actual, ok := responseUsage(body); ok {
+		l.reconcile(estimated, actual)
+	}
+	return nil
+}
+
+// tokenBucket is a token bucket that allows its balance to go negative, so that
+// a large reservation delays later callers rather than being starved.
+type tokenBucket struct {
+	mu       sync.Mutex
+	rate     float64 // Tokens added per second.
+	capacity float64
+	tokens   float64
+	last     time.Time
+}
+
+func newTokenBucket(rate, capacity float64) *tokenBucket {
+	return &tokenBucket{
+		rate:     rate,
+		capacity: capacity,
+		tokens:   capacity,
+		last:     time.Now(),
+	}
+}
+
+// take removes n tokens and returns how long the caller must wait until the
+// bucket is no longer in debt. A nil bucket never blocks.
+func (b *tokenBucket) take(n float64) time.Duration {
+	if b == nil {
+		return 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	b.tokens -= n
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// refund returns n tokens to the bucket. A negative n removes tokens.
+func (b *tokenBucket) refund(n float64) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	b.tokens = min(b.tokens+n, b.capacity)
+}
+
+func (b *tokenBucket) refill() {
+	now := time.Now()
+	b.tokens = min(b.tokens+now.Sub(b.last).Seconds()*b.rate, b.capacity)
+	b.last = now
+}
+
+// estimateRequestTokens estimates the tokens a request will consume from its
+// JSON body: the prompt, messages or input text plus the requested max_tokens.
+func estimateRequestTokens(body []byte) int {
+	var request struct {
+		Prompt    string            `json:"prompt"`
+		Messages  []json.RawMessage `json:"messages"`
+		Input     json.RawMessage   `json:"input"`
+		MaxTokens int               `json:"max_tokens"`
+	}
+	if err := json.Unmarshal(body, &request); err != nil {
+		return estimateTextTokens(string(body))
+	}
+
+	chars := len(request.Prompt) + len(request.Input)
+	for _, message := range request.Messages {
+		chars += len(message)
+	}
+	return (chars+charsPerToken-1)/charsPerToken + request.MaxTokens
+}
+
+// estimateTextTokens estimates the number of tokens in text.
+func estimateTextTokens(text string) int {
+	return (len(text) + charsPerToken - 1) / charsPerToken
+}
+
+// responseUsage returns the total_tokens reported in a JSON response body.
+func responseUsage(body []byte) (int, bool) {
+	var response struct {
+		Usage *UsageObject `json:"usage"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil || response.Usage == nil {
+		return 0, false
+	}
+	return response.Usage.TotalTokens, true
+}
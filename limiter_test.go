@@ -0,0 +1,103 @@
+package together
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Test reserving and refunding tokens from a token bucket.
+func TestTokenBucket(t *testing.T) {
+	b := newTokenBucket(10, 10)
+
+	// Case: Reservations within capacity do not wait
+	if delay := b.take(10); delay != 0 {
+		t.Errorf("Result was incorrect, got: %s, want: %s.", delay, time.Duration(0))
+	}
+
+	// Case: Reservations beyond capacity wait for the debt to be repaid
+	delay := b.take(5)
+	if delay < 400*time.Millisecond || delay > 500*time.Millisecond {
+		t.Errorf("Result was incorrect, got: %s, want: ~%s.", delay, 500*time.Millisecond)
+	}
+
+	// Case: Refunds never exceed capacity
+	b.refund(100)
+	if b.tokens != 10 {
+		t.Errorf("Result was incorrect, got: %f, want: %d.", b.tokens, 10)
+	}
+
+	// Case: A nil bucket never blocks
+	var nilBucket *tokenBucket
+	if delay := nilBucket.take(100); delay != 0 {
+		t.Errorf("Result was incorrect, got: %s, want: %s.", delay, time.Duration(0))
+	}
+}
+
+// Test waiting on the limiter.
+func TestLimiterWait(t *testing.T) {
+	l := newLimiter(0, 60)
+
+	// Case: Within budget
+	if err := l.wait(context.Background(), 60); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	// Case: Cancelled while waiting releases the reservation
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := l.wait(ctx, 30)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, context.DeadlineExceeded)
+	}
+	if l.tokens.tokens > 1 {
+		t.Errorf("Result was incorrect, got: %f, want: ~%d.", l.tokens.tokens, 0)
+	}
+
+	// Case: Reconciling returns over-estimated tokens
+	l.reconcile(60, 0)
+	if err := l.wait(context.Background(), 59); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+// Test estimating request tokens.
+func TestEstimateRequestTokens(t *testing.T) {
+	cases := map[string]int{
+		`{"prompt":"abcdefgh","max_tokens":10}`: 12,
+		`{"input":"abcdef"}`:                    2,
+		`not json`:                              2,
+	}
+
+	for body, want := range cases {
+		if got := estimateRequestTokens([]byte(body)); got != want {
+			t.Errorf("Result was incorrect for %s, got: %d, want: %d.", body, got, want)
+		}
+	}
+}
+
+// Test that the limiter reconciles with the reported usage.
+func TestRequestWithRateLimit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintln(w, `{"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`)
+	}))
+	defer ts.Close()
+
+	req, _ := New("hunter2", WithBaseURL(ts.URL), WithRateLimit(0, 600))
+
+	resp, err := req.Completions(context.Background(), "a", "b", 500, CompletionsRequest{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.Usage.TotalTokens != 2 {
+		t.Errorf("Result was incorrect, got: %d, want: %d.", resp.Usage.TotalTokens, 2)
+	}
+	if tokens := req.limiter.tokens.tokens; tokens < 597 {
+		t.Errorf("Result was incorrect, got: %f, want: %d.", tokens, 598)
+	}
+}
@@ -0,0 +1,151 @@
+package together
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+type ModelType string
+
+const (
+	ModelTypeChat       ModelType = "chat"
+	ModelTypeLanguage   ModelType = "language"
+	ModelTypeCode       ModelType = "code"
+	ModelTypeEmbedding  ModelType = "embedding"
+	ModelTypeImage      ModelType = "image"
+	ModelTypeModeration ModelType = "moderation"
+	ModelTypeRerank     ModelType = "rerank"
+)
+
+// ModelPricing holds the price of a model in USD. Token prices are per
+// million tokens.
+type ModelPricing struct {
+	Input    float64 `json:"input"`
+	Output   float64 `json:"output"`
+	Hourly   float64 `json:"hourly"`
+	Base     float64 `json:"base"`
+	Finetune float64 `json:"finetune"`
+}
+
+// Cost returns the price in USD of a request with the given token counts.
+func (p ModelPricing) Cost(inputTokens, outputTokens int) float64 {
+	return (float64(inputTokens)*p.Input + float64(outputTokens)*p.Output) / 1e6
+}
+
+type Model struct {
+	Id            string       `json:"id"` // Name of the model, as passed to the other endpoints.
+	Object        string       `json:"object"`
+	Created       int64        `json:"created"` // Unix timestamp.
+	Type          ModelType    `json:"type"`
+	DisplayName   string       `json:"display_name"`
+	Organization  string       `json:"organization"`
+	Link          string       `json:"link"`
+	License       string       `json:"license"`
+	ContextLength int          `json:"context_length"`
+	Pricing       ModelPricing `json:"pricing"`
+}
+
+// ModelList is a list of models in the order they were returned.
+type ModelList []Model
+
+// Get returns the model with the given id.
+func (l ModelList) Get(id string) (Model, bool) {
+	for _, model := range l {
+		if model.Id == id {
+			return model, true
+		}
+	}
+	return Model{}, false
+}
+
+// Filter returns the models for which keep returns true.
+func (l ModelList) Filter(keep func(Model) bool) ModelList {
+	var models ModelList
+	for _, model := range l {
+		if keep(model) {
+			models = append(models, model)
+		}
+	}
+	return models
+}
+
+// OfType returns the models of any of the given types.
+func (l ModelList) OfType(types ...ModelType) ModelList {
+	return l.Filter(func(model Model) bool {
+		for _, t := range types {
+			if model.Type == t {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// WithContextLength returns the models with a context length of at least
+// tokens.
+func (l ModelList) WithContextLength(tokens int) ModelList {
+	return l.Filter(func(model Model) bool {
+		return model.ContextLength >= tokens
+	})
+}
+
+// UnmarshalJSON accepts either an array of models or a list object with the
+// models in "data".
+func (l *ModelList) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var models []Model
+		if err := json.Unmarshal(data, &models); err != nil {
+			return err
+		}
+		*l = models
+		return nil
+	}
+
+	var list struct {
+		Data []Model `json:"data"`
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*l = list.Data
+	return nil
+}
+
+// List Models is the endpoint for listing the models available to the
+// account, including its fine-tuned models.
+//
+// API Reference: https://docs.together.ai/reference/models-1
+func (api *API) ListModels(ctx context.Context) (ModelList, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("no context provided")
+	}
+
+	uri := defaultBasePath + Version + "/models"
+
+	res, err := api.request(ctx, "GET", uri, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, newAPIError(res, body)
+	}
+
+	var modelList ModelList
+	err = json.Unmarshal(body, &modelList)
+	if err != nil {
+		return nil, err
+	}
+
+	return modelList, nil
+}
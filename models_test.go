@@ -0,0 +1,141 @@
+package together
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestListModels(t *testing.T) {
+	req, _ := New("hunter2")
+	req.Client.RetryMax = 1
+	req.BaseURL = ""
+
+	// Case: ListModels Fails with no context
+	resp, err := req.ListModels(nil) //lint:ignore SA1012 nil context used intentionally
+	if resp != nil {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", resp, nil)
+	}
+	if err == nil {
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "no context provided")
+	}
+
+	// Case: ListModels Fails with invalid HTTP request
+	if _, err := req.ListModels(context.TODO()); err == nil {
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "!nil")
+	}
+
+	// Case: ListModels Fails with HTTP 200 and malformed body
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "Hello, client")
+	}))
+
+	req.BaseURL = ts.URL
+
+	if _, err := req.ListModels(context.TODO()); err == nil {
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "!nil")
+	}
+
+	ts.Close()
+
+	// Case: ListModels Fails with HTTP 401
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprintln(w, `{"error":{"message":"invalid api key"}}`)
+	}))
+
+	req.BaseURL = ts.URL
+
+	if _, err := req.ListModels(context.TODO()); !IsAuth(err) {
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "invalid api key")
+	}
+
+	ts.Close()
+
+	// Case: ListModels Succeeds with an array of models
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/models" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintln(w, `[
+			{"id":"meta-llama/Llama-3-8b-chat-hf","object":"model","created":1713000000,"type":"chat","display_name":"Llama 3 8B Chat","organization":"Meta","license":"Llama-3 (Other)","context_length":8192,"pricing":{"input":0.2,"output":0.2}},
+			{"id":"meta-llama/Llama-3-70b-chat-hf","object":"model","type":"chat","organization":"Meta","context_length":8192,"pricing":{"input":0.9,"output":0.9}},
+			{"id":"BAAI/bge-large-en-v1.5","object":"model","type":"embedding","organization":"BAAI","context_length":512,"pricing":{"input":0.02}},
+			{"id":"Salesforce/Llama-Rank-V1","object":"model","type":"rerank","organization":"Salesforce","context_length":8192,"pricing":{"input":0.1}}
+		]`)
+	}))
+	defer ts.Close()
+
+	req.BaseURL = ts.URL
+
+	models, err := req.ListModels(context.TODO())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(models) != 4 {
+		t.Fatalf("Result was incorrect, got: %d models, want: %d.", len(models), 4)
+	}
+	want := Model{
+		Id:            "meta-llama/Llama-3-8b-chat-hf",
+		Object:        "model",
+		Created:       1713000000,
+		Type:          ModelTypeChat,
+		DisplayName:   "Llama 3 8B Chat",
+		Organization:  "Meta",
+		License:       "Llama-3 (Other)",
+		ContextLength: 8192,
+		Pricing:       ModelPricing{Input: 0.2, Output: 0.2},
+	}
+	if !reflect.DeepEqual(models[0], want) {
+		t.Errorf("Result was incorrect, got: %+v, want: %+v.", models[0], want)
+	}
+
+	// Case: Get
+	if model, ok := models.Get("BAAI/bge-large-en-v1.5"); !ok || model.Type != ModelTypeEmbedding {
+		t.Errorf("Result was incorrect, got: %+v, %v.", model, ok)
+	}
+	if _, ok := models.Get("unknown/model"); ok {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", ok, false)
+	}
+
+	// Case: Filter helpers
+	chat := models.OfType(ModelTypeChat)
+	if len(chat) != 2 || chat[1].Id != "meta-llama/Llama-3-70b-chat-hf" {
+		t.Errorf("Result was incorrect, got: %+v.", chat)
+	}
+	if ranked := models.OfType(ModelTypeEmbedding, ModelTypeRerank).WithContextLength(1024); len(ranked) != 1 || ranked[0].Id != "Salesforce/Llama-Rank-V1" {
+		t.Errorf("Result was incorrect, got: %+v.", ranked)
+	}
+	cheap := models.Filter(func(model Model) bool { return model.Organization == "Meta" && model.Pricing.Output < 0.5 })
+	if len(cheap) != 1 || cheap[0].Id != want.Id {
+		t.Errorf("Result was incorrect, got: %+v.", cheap)
+	}
+	if images := models.OfType(ModelTypeImage); images != nil {
+		t.Errorf("Result was incorrect, got: %+v, want: %v.", images, nil)
+	}
+
+	// Case: Pricing per request
+	if cost := want.Pricing.Cost(1_000_000, 500_000); cost != 0.3 {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", cost, 0.3)
+	}
+}
+
+func TestModelListUnmarshal(t *testing.T) {
+	// Case: A list object
+	var models ModelList
+	if err := models.UnmarshalJSON([]byte(`{"object":"list","data":[{"id":"a","type":"image"}]}`)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(models, ModelList{{Id: "a", Type: ModelTypeImage}}) {
+		t.Errorf("Result was incorrect, got: %+v.", models)
+	}
+
+	// Case: Malformed JSON
+	if err := models.UnmarshalJSON([]byte(`[{"id":1}]`)); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "!nil")
+	}
+}
@@ -0,0 +1,111 @@
+package together
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Option is a functional option for configuring the API client.
+type Option func(*API) error
+
+// Logger is the interface used for debug output. It is satisfied by *log.Logger.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// WithBaseURL overrides the default API base URL, e.g. for a proxy.
+func WithBaseURL(baseURL string) Option {
+	return func(api *API) error {
+		u, err := url.Parse(baseURL)
+		if err != nil {
+			return fmt.Errorf("invalid base URL: %w", err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid base URL: %q must include a scheme and host", baseURL)
+		}
+
+		api.BaseURL = strings.TrimSuffix(baseURL, "/")
+		return nil
+	}
+}
+
+// WithHTTPClient sets the underlying HTTP client used for all requests.
+func WithHTTPClient(client *http.Client) Option {
+	return func(api *API) error {
+		if client == nil {
+			return errors.New("invalid HTTP client: must not be nil")
+		}
+
+		api.Client.HTTPClient = client
+		return nil
+	}
+}
+
+// WithRetryMax sets the maximum number of retries for a failed request.
+func WithRetryMax(retries int) Option {
+	return func(api *API) error {
+		if retries < 0 {
+			return errors.New("invalid retry count: must not be negative")
+		}
+
+		api.Client.RetryMax = retries
+		return nil
+	}
+}
+
+// WithTimeout sets the timeout for each HTTP attempt, including reading the
+// response body. Streaming calls should prefer a context deadline instead.
+//
+// The timeout is applied to a copy of the final HTTP client, so a client passed
+// to WithHTTPClient is not modified, whatever the order of the options.
+func WithTimeout(timeout time.Duration) Option {
+	return func(api *API) error {
+		if timeout < 0 {
+			return errors.New("invalid timeout: must not be negative")
+		}
+
+		api.timeout = &timeout
+		return nil
+	}
+}
+
+// WithHeaders sets additional headers that are sent with every request.
+func WithHeaders(headers http.Header) Option {
+	return func(api *API) error {
+		copyHeader(api.headers, headers.Clone())
+		return nil
+	}
+}
+
+// WithUserAgent overrides the default User-Agent header.
+func WithUserAgent(userAgent string) Option {
+	return func(api *API) error {
+		api.UserAgent = userAgent
+		return nil
+	}
+}
+
+// WithLogger sets the logger used for debug output and retry messages.
+func WithLogger(logger Logger) Option {
+	return func(api *API) error {
+		if logger == nil {
+			return errors.New("invalid logger: must not be nil")
+		}
+
+		api.logger = logger
+		api.Client.Logger = logger
+		return nil
+	}
+}
+
+// WithDebug enables dumping of requests and responses to the logger.
+func WithDebug(debug bool) Option {
+	return func(api *API) error {
+		api.Debug = debug
+		return nil
+	}
+}
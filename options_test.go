@@ -0,0 +1,114 @@
+package together
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test creating a new API object/client with options.
+func TestNewWithOptions(t *testing.T) {
+	client := &http.Client{}
+	var buf bytes.Buffer
+	logger := log.New(&buf, "", 0)
+
+	api, err := New("hunter2",
+		WithBaseURL("https://example.com/"),
+		WithHTTPClient(client),
+		WithRetryMax(2),
+		WithTimeout(time.Second),
+		WithHeaders(http.Header{"X-Test": []string{"test"}}),
+		WithUserAgent("test-agent"),
+		WithLogger(logger),
+		WithDebug(true),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if api.BaseURL != "https://example.com" {
+		t.Errorf("Result was incorrect, got: %s, want: %s.", api.BaseURL, "https://example.com")
+	}
+	if api.Client.RetryMax != 2 {
+		t.Errorf("Result was incorrect, got: %d, want: %d.", api.Client.RetryMax, 2)
+	}
+	if api.Client.HTTPClient == client || api.Client.HTTPClient.Timeout != time.Second {
+		t.Errorf("Result was incorrect, got: %v, want: a copy with a %s timeout.", api.Client.HTTPClient, time.Second)
+	}
+	if client.Timeout != 0 {
+		t.Errorf("Result was incorrect, got: %s, want: %s.", client.Timeout, time.Duration(0))
+	}
+	// Case: The timeout is applied whatever the order of the options
+	for name, opts := range map[string][]Option{
+		"timeout first": {WithTimeout(time.Second), WithHTTPClient(client)},
+		"timeout last":  {WithHTTPClient(client), WithTimeout(time.Second)},
+	} {
+		api, err := New("hunter2", opts...)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if api.Client.HTTPClient == client || api.Client.HTTPClient.Timeout != time.Second {
+			t.Errorf("Result was incorrect with %s, got: %v, want: a copy with a %s timeout.", name, api.Client.HTTPClient, time.Second)
+		}
+	}
+	if client.Timeout != 0 {
+		t.Errorf("Result was incorrect, got: %s, want: %s.", client.Timeout, time.Duration(0))
+	}
+
+	if api.headers.Get("X-Test") != "test" {
+		t.Errorf("Result was incorrect, got: %s, want: %s.", api.headers.Get("X-Test"), "test")
+	}
+	if api.UserAgent != "test-agent" || !api.Debug || api.logger != logger {
+		t.Errorf("Result was incorrect, got: %+v.", api)
+	}
+
+	// Case: Headers and User-Agent are sent with requests, and debug output is logged
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Test") != "test" {
+			t.Errorf("Result was incorrect, got: %s, want: %s.", r.Header.Get("X-Test"), "test")
+		}
+		if r.Header.Get("User-Agent") != "test-agent" {
+			t.Errorf("Result was incorrect, got: %s, want: %s.", r.Header.Get("User-Agent"), "test-agent")
+		}
+		fmt.Fprintln(w, "{}")
+	}))
+	defer ts.Close()
+
+	api, _ = New("hunter2", WithBaseURL(ts.URL), WithHeaders(http.Header{"X-Test": []string{"test"}}), WithUserAgent("test-agent"), WithLogger(logger), WithDebug(true))
+	resp, err := api.request(context.Background(), "GET", "/", nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if !strings.Contains(buf.String(), "[redacted]") {
+		t.Errorf("Result was incorrect, got: %s, want: %s.", buf.String(), "[redacted]")
+	}
+	if strings.Contains(buf.String(), "hunter2") {
+		t.Errorf("Result was incorrect, got: %s, want: no API key.", buf.String())
+	}
+}
+
+// Test that invalid options are rejected.
+func TestNewWithInvalidOptions(t *testing.T) {
+	cases := map[string]Option{
+		"base URL":    WithBaseURL("example.com"),
+		"HTTP client": WithHTTPClient(nil),
+		"retries":     WithRetryMax(-1),
+		"timeout":     WithTimeout(-time.Second),
+		"logger":      WithLogger(nil),
+	}
+
+	for name, opt := range cases {
+		api, err := New("hunter2", opt)
+		if api != nil || err == nil {
+			t.Errorf("Result was incorrect for %s, got: %v, want: error.", name, err)
+		}
+	}
+}
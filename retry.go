@@ -0,0 +1,180 @@
+package together
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// RateLimit is the rate-limit state reported by the Together API on the most
+// recent response. Counts are -1 and reset times are zero when the
+// corresponding header was not sent.
+type RateLimit struct {
+	LimitRequests     int
+	RemainingRequests int
+	ResetRequests     time.Time
+	LimitTokens       int
+	RemainingTokens   int
+	ResetTokens       time.Time
+}
+
+type methodContextKey struct{}
+
+// RateLimit returns the rate-limit state observed on the most recent response.
+func (api *API) RateLimit() RateLimit {
+	api.rateLimitMu.Lock()
+	defer api.rateLimitMu.Unlock()
+
+	if api.rateLimit == nil {
+		return RateLimit{LimitRequests: -1, RemainingRequests: -1, LimitTokens: -1, RemainingTokens: -1}
+	}
+	return *api.rateLimit
+}
+
+func (api *API) recordRateLimit(header http.Header) {
+	rl, ok := parseRateLimit(header, time.Now())
+	if !ok {
+		return
+	}
+
+	api.rateLimitMu.Lock()
+	defer api.rateLimitMu.Unlock()
+	api.rateLimit = &rl
+}
+
+// parseRateLimit reads the x-ratelimit-* headers. Both the plain names and the
+// -requests suffixed variants are accepted for the request budget.
+func parseRateLimit(header http.Header, now time.Time) (RateLimit, bool) {
+	rl := RateLimit{
+		LimitRequests:     headerInt(header, "X-Ratelimit-Limit-Requests", "X-Ratelimit-Limit"),
+		RemainingRequests: headerInt(header, "X-Ratelimit-Remaining-Requests", "X-Ratelimit-Remaining"),
+		ResetRequests:     headerReset(header, now, "X-Ratelimit-Reset-Requests", "X-Ratelimit-Reset"),
+		LimitTokens:       headerInt(header, "X-Ratelimit-Limit-Tokens"),
+		RemainingTokens:   headerInt(header, "X-Ratelimit-Remaining-Tokens"),
+		ResetTokens:       headerReset(header, now, "X-Ratelimit-Reset-Tokens"),
+	}
+
+	ok := rl != RateLimit{LimitRequests: -1, RemainingRequests: -1, LimitTokens: -1, RemainingTokens: -1}
+	return rl, ok
+}
+
+func headerInt(header http.Header, keys ...string) int {
+	for _, key := range keys {
+		if v, err := strconv.ParseFloat(header.Get(key), 64); err == nil {
+			return int(v)
+		}
+	}
+	return -1
+}
+
+// headerReset parses a reset header given either as seconds until the reset,
+// a Unix timestamp, or a Go duration string such as "1m30s".
+func headerReset(header http.Header, now time.Time, keys ...string) time.Time {
+	for _, key := range keys {
+		v := header.Get(key)
+		if v == "" {
+			continue
+		}
+		if secs, err := strconv.ParseFloat(v, 64); err == nil {
+			if secs > 1e9 {
+				return time.Unix(0, int64(secs*float64(time.Second)))
+			}
+			return now.Add(time.Duration(secs * float64(time.Second)))
+		}
+		if d, err := time.ParseDuration(v); err == nil {
+			return now.Add(d)
+		}
+	}
+	return time.Time{}
+}
+
+// retryAfter returns how long the server asked us to wait, preferring the
+// Retry-After header and falling back to the reset time of whichever
+// rate-limit budget has been exhausted.
+func retryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	if v := header.Get("Retry-After"); v != "" {
+		if secs, err := strconv.ParseFloat(v, 64); err == nil && secs >= 0 {
+			return time.Duration(secs * float64(time.Second)), true
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return max(t.Sub(now), 0), true
+		}
+	}
+
+	rl, ok := parseRateLimit(header, now)
+	if !ok {
+		return 0, false
+	}
+
+	// A budget with an unknown remaining count (-1) may be the exhausted one.
+	var reset time.Time
+	if rl.RemainingRequests <= 0 && rl.ResetRequests.After(reset) {
+		reset = rl.ResetRequests
+	}
+	if rl.RemainingTokens <= 0 && rl.ResetTokens.After(reset) {
+		reset = rl.ResetTokens
+	}
+	if reset.IsZero() {
+		return 0, false
+	}
+	return max(reset.Sub(now), 0), true
+}
+
+// checkRetry extends retryablehttp.DefaultRetryPolicy. Rate-limited and
+// unavailable responses are always retried. Non-idempotent requests are
+// otherwise only retried when the connection was never established, as the
+// server may already have acted on the request, e.g. before failing with a 502.
+func checkRetry(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	method, _ := ctx.Value(methodContextKey{}).(string)
+	if err != nil {
+		if !isIdempotent(method) && !isDialError(err) {
+			return false, nil
+		}
+		return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		return true, nil
+	}
+	if !isIdempotent(method) {
+		return false, nil
+	}
+
+	return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+}
+
+// backoff waits exactly as long as the server instructs on 429 and 503
+// responses, and otherwise uses retryablehttp's exponential backoff.
+func backoff(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if wait, ok := retryAfter(resp.Header, time.Now()); ok {
+			return wait
+		}
+	}
+
+	return retryablehttp.DefaultBackoff(min, max, attemptNum, resp)
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isDialError reports whether err occurred while establishing the connection,
+// meaning no part of the request was sent.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
@@ -0,0 +1,152 @@
+package together
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Test parsing rate-limit headers.
+func TestParseRateLimit(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	// Case: No rate-limit headers
+	_, ok := parseRateLimit(http.Header{}, now)
+	if ok {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", ok, false)
+	}
+
+	// Case: Plain and token headers
+	header := make(http.Header)
+	header.Set("X-Ratelimit-Limit", "100")
+	header.Set("X-Ratelimit-Remaining", "0")
+	header.Set("X-Ratelimit-Reset", "2")
+	header.Set("X-Ratelimit-Limit-Tokens", "1000")
+	header.Set("X-Ratelimit-Remaining-Tokens", "500")
+	header.Set("X-Ratelimit-Reset-Tokens", "1m0s")
+
+	rl, ok := parseRateLimit(header, now)
+	want := RateLimit{
+		LimitRequests:     100,
+		RemainingRequests: 0,
+		ResetRequests:     now.Add(2 * time.Second),
+		LimitTokens:       1000,
+		RemainingTokens:   500,
+		ResetTokens:       now.Add(time.Minute),
+	}
+	if !ok || rl != want {
+		t.Errorf("Result was incorrect, got: %+v, want: %+v.", rl, want)
+	}
+
+	// Case: Only the exhausted budget is used to compute the wait
+	wait, ok := retryAfter(header, now)
+	if !ok || wait != 2*time.Second {
+		t.Errorf("Result was incorrect, got: %s, want: %s.", wait, 2*time.Second)
+	}
+
+	// Case: Retry-After takes precedence
+	header.Set("Retry-After", "5")
+	wait, ok = retryAfter(header, now)
+	if !ok || wait != 5*time.Second {
+		t.Errorf("Result was incorrect, got: %s, want: %s.", wait, 5*time.Second)
+	}
+
+	// Case: Retry-After as HTTP date
+	header.Set("Retry-After", now.Add(3*time.Second).UTC().Format(http.TimeFormat))
+	wait, ok = retryAfter(header, now)
+	if !ok || wait != 3*time.Second {
+		t.Errorf("Result was incorrect, got: %s, want: %s.", wait, 3*time.Second)
+	}
+}
+
+// Test the retry policy against a live server.
+func TestRetryPolicy(t *testing.T) {
+	// Case: 429 is retried after the Retry-After delay and the rate limit is recorded
+	var attempts int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Ratelimit-Limit", "10")
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.Header().Set("X-Ratelimit-Remaining", "0")
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("X-Ratelimit-Remaining", "9")
+		fmt.Fprintln(w, "{}")
+	}))
+
+	req, _ := New("hunter2", WithBaseURL(ts.URL), WithRetryMax(1))
+	if rl := req.RateLimit(); rl.RemainingRequests != -1 {
+		t.Errorf("Result was incorrect, got: %d, want: %d.", rl.RemainingRequests, -1)
+	}
+
+	resp, err := req.request(context.Background(), "POST", "/", nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if atomic.LoadInt32(&attempts) != 2 {
+		t.Errorf("Result was incorrect, got: %d, want: %d.", attempts, 2)
+	}
+	if rl := req.RateLimit(); rl.LimitRequests != 10 || rl.RemainingRequests != 9 {
+		t.Errorf("Result was incorrect, got: %+v.", rl)
+	}
+
+	ts.Close()
+
+	// Case: A POST whose connection drops after sending is not retried, a GET is
+	atomic.StoreInt32(&attempts, 0)
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.Close()
+	}))
+	defer ts.Close()
+
+	req, _ = New("hunter2", WithBaseURL(ts.URL), WithRetryMax(1))
+	req.Client.RetryWaitMin = time.Millisecond
+	req.Client.RetryWaitMax = time.Millisecond
+
+	_, err = req.request(context.Background(), "POST", "/", nil, nil)
+	if err == nil || atomic.LoadInt32(&attempts) != 1 {
+		t.Errorf("Result was incorrect, got: %d attempts, want: %d.", attempts, 1)
+	}
+
+	atomic.StoreInt32(&attempts, 0)
+	_, err = req.request(context.Background(), "GET", "/", nil, nil)
+	if err == nil || atomic.LoadInt32(&attempts) != 2 {
+		t.Errorf("Result was incorrect, got: %d attempts, want: %d.", attempts, 2)
+	}
+
+	// Case: A POST that gets a 502 is sent exactly once, a GET is retried
+	var bad int32
+	bts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&bad, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer bts.Close()
+
+	req, _ = New("hunter2", WithBaseURL(bts.URL), WithRetryMax(3))
+	req.Client.RetryWaitMin = time.Millisecond
+	req.Client.RetryWaitMax = time.Millisecond
+
+	resp, err = req.request(context.Background(), "POST", "/v1/fine-tunes", nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadGateway || atomic.LoadInt32(&bad) != 1 {
+		t.Errorf("Result was incorrect, got: %d attempts, want: %d.", bad, 1)
+	}
+
+	atomic.StoreInt32(&bad, 0)
+	_, _ = req.request(context.Background(), "GET", "/v1/fine-tunes", nil, nil)
+	if atomic.LoadInt32(&bad) != 4 {
+		t.Errorf("Result was incorrect, got: %d attempts, want: %d.", bad, 4)
+	}
+}
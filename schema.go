@@ -0,0 +1,443 @@
+package together
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const ResponseFormatJSONObject = "json_object"
+
+// Schema is the subset of JSON Schema generated from Go types and used for
+// tool parameters and structured output.
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"` // Whether null is also accepted.
+	Description          string             `json:"description,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties any                `json:"additionalProperties,omitempty"` // Either false or a *Schema.
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	numberType     = reflect.TypeOf(json.Number(""))
+)
+
+// GenerateSchema generates a JSON Schema for the type of v.
+//
+// Property names follow the `json` struct tags. Fields are required unless
+// tagged omitempty or of pointer type. Pointers, slices and maps are nullable,
+// as encoding/json encodes their nil values as null. The `jsonschema` struct tag sets additional keywords as a
+// comma separated list, e.g. `jsonschema:"description=City name,enum=Oslo|Bergen,required"`;
+// a literal comma in a description is escaped as `\\,` in the tag source.
+func GenerateSchema(v any) (*Schema, error) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return nil, fmt.Errorf("cannot generate a schema for nil")
+	}
+	return schemaForType(t, map[reflect.Type]bool{})
+}
+
+// SchemaFor generates a JSON Schema for the type T.
+func SchemaFor[T any]() (*Schema, error) {
+	return schemaForType(reflect.TypeOf((*T)(nil)).Elem(), map[reflect.Type]bool{})
+}
+
+// NewFunctionTool builds a function Tool whose parameters are described by the
+// struct type T.
+func NewFunctionTool[T any](name, description string) (Tool, error) {
+	schema, err := SchemaFor[T]()
+	if err != nil {
+		return Tool{}, err
+	}
+	if schema.Type != "object" {
+		return Tool{}, fmt.Errorf("function parameters must be a struct or map, got %s", schema.Type)
+	}
+
+	parameters, err := json.Marshal(schema)
+	if err != nil {
+		return Tool{}, err
+	}
+
+	return Tool{
+		Type: "function",
+		Function: FunctionObject{
+			Name:        name,
+			Description: description,
+			Parameters:  parameters,
+		},
+	}, nil
+}
+
+// NewResponseFormat builds a JSON ResponseFormatObject constrained to the schema of T.
+func NewResponseFormat[T any]() (ResponseFormatObject, error) {
+	schema, err := SchemaFor[T]()
+	if err != nil {
+		return ResponseFormatObject{}, err
+	}
+
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return ResponseFormatObject{}, err
+	}
+
+	return ResponseFormatObject{Type: ResponseFormatJSONObject, Schema: raw}, nil
+}
+
+func schemaForType(t reflect.Type, seen map[reflect.Type]bool) (*Schema, error) {
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		nullable = true
+	}
+
+	schema, err := schemaForElem(t, seen)
+	if err != nil {
+		return nil, err
+	}
+	if schema.Type != "" {
+		schema.Nullable = nullable || t.Kind() == reflect.Slice || t.Kind() == reflect.Map
+	}
+	return schema, nil
+}
+
+// schemaForElem generates the schema of a type that is not a pointer.
+func schemaForElem(t reflect.Type, seen map[reflect.Type]bool) (*Schema, error) {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}, nil
+	case rawMessageType:
+		return &Schema{}, nil
+	case numberType:
+		return &Schema{Type: "number"}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}, nil
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+	case reflect.Interface:
+		return &Schema{}, nil
+	case reflect.Slice, reflect.Array:
+		// []byte is encoded as a base64 string by encoding/json.
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string"}, nil
+		}
+		items, err := schemaForType(t.Elem(), seen)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		switch t.Key().Kind() {
+		case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		default:
+			return nil, fmt.Errorf("unsupported map key type %s", t.Key())
+		}
+		values, err := schemaForType(t.Elem(), seen)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		if seen[t] {
+			return nil, fmt.Errorf("recursive type %s is not supported", t)
+		}
+		seen[t] = true
+		defer delete(seen, t)
+
+		schema := &Schema{Type: "object", Properties: map[string]*Schema{}, AdditionalProperties: false}
+		if err := addStructFields(schema, t, seen); err != nil {
+			return nil, err
+		}
+		return schema, nil
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// addStructFields adds the fields of t to schema, flattening embedded structs
+// the same way encoding/json does.
+func addStructFields(schema *Schema, t reflect.Type, seen map[reflect.Type]bool) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if err := addStructFields(schema, ft, seen); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop, err := schemaForType(field.Type, seen)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		if hasTagOption(opts, "string") {
+			prop = &Schema{Type: "string"}
+		}
+
+		required := !hasTagOption(opts, "omitempty") && field.Type.Kind() != reflect.Pointer
+		if tag, ok := field.Tag.Lookup("jsonschema"); ok {
+			if required, err = applySchemaTag(prop, tag, required); err != nil {
+				return fmt.Errorf("field %s: %w", field.Name, err)
+			}
+		}
+
+		schema.Properties[name] = prop
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return nil
+}
+
+// applySchemaTag applies the keywords of a `jsonschema` struct tag to prop and
+// returns whether the field is required.
+func applySchemaTag(prop *Schema, tag string, required bool) (bool, error) {
+	for _, keyword := range splitSchemaTag(tag) {
+		key, value, _ := strings.Cut(keyword, "=")
+		switch strings.TrimSpace(key) {
+		case "description":
+			prop.Description = value
+		case "format":
+			prop.Format = value
+		case "required":
+			required = true
+		case "optional":
+			required = false
+		case "enum":
+			for _, v := range strings.Split(value, "|") {
+				enum, err := parseEnumValue(prop.Type, v)
+				if err != nil {
+					return required, err
+				}
+				prop.Enum = append(prop.Enum, enum)
+			}
+		case "":
+		default:
+			return required, fmt.Errorf("unknown jsonschema keyword %q", key)
+		}
+	}
+	return required, nil
+}
+
+// splitSchemaTag splits a tag on commas that are not escaped with a backslash.
+func splitSchemaTag(tag string) []string {
+	var parts []string
+	var b strings.Builder
+	for i := 0; i < len(tag); i++ {
+		switch {
+		case tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ',':
+			b.WriteByte(',')
+			i++
+		case tag[i] == ',':
+			parts = append(parts, b.String())
+			b.Reset()
+		default:
+			b.WriteByte(tag[i])
+		}
+	}
+	return append(parts, b.String())
+}
+
+func parseEnumValue(typ, value string) (any, error) {
+	switch typ {
+	case "integer":
+		return strconv.ParseInt(value, 10, 64)
+	case "number":
+		return strconv.ParseFloat(value, 64)
+	case "boolean":
+		return strconv.ParseBool(value)
+	}
+	return value, nil
+}
+
+func hasTagOption(opts, option string) bool {
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// SchemaValidationError describes where a value does not match a Schema.
+type SchemaValidationError struct {
+	Path    string // JSON path of the invalid value, e.g. "$.address.city".
+	Message string
+}
+
+func (e *SchemaValidationError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// Validate checks a decoded JSON value against the schema. Numbers must be
+// decoded as json.Number, e.g. using json.Decoder.UseNumber.
+func (s *Schema) Validate(v any) error {
+	return s.validate("$", v)
+}
+
+func (s *Schema) validate(path string, v any) error {
+	if s == nil || (v == nil && s.Nullable) {
+		return nil
+	}
+
+	invalid := func(format string, args ...any) error {
+		return &SchemaValidationError{Path: path, Message: fmt.Sprintf(format, args...)}
+	}
+
+	switch s.Type {
+	case "":
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return invalid("expected object, got %s", jsonTypeName(v))
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				return invalid("missing required property %q", name)
+			}
+		}
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			value := obj[name]
+			prop, ok := s.Properties[name]
+			if !ok {
+				switch additional := s.AdditionalProperties.(type) {
+				case bool:
+					if !additional {
+						return invalid("unexpected property %q", name)
+					}
+				case *Schema:
+					prop = additional
+				}
+			}
+			if err := prop.validate(path+"."+name, value); err != nil {
+				return err
+			}
+		}
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			return invalid("expected array, got %s", jsonTypeName(v))
+		}
+		for i, item := range arr {
+			if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+				return err
+			}
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return invalid("expected string, got %s", jsonTypeName(v))
+		}
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				return invalid("expected RFC 3339 date-time, got %q", str)
+			}
+		}
+	case "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			return invalid("expected integer, got %s", jsonTypeName(v))
+		}
+		if _, err := n.Int64(); err != nil {
+			return invalid("expected integer, got %s", n)
+		}
+	case "number":
+		if _, ok := v.(json.Number); !ok {
+			return invalid("expected number, got %s", jsonTypeName(v))
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return invalid("expected boolean, got %s", jsonTypeName(v))
+		}
+	default:
+		return invalid("unsupported schema type %q", s.Type)
+	}
+
+	if len(s.Enum) > 0 && !enumContains(s.Enum, v) {
+		return invalid("value %v is not one of %v", v, s.Enum)
+	}
+	return nil
+}
+
+func enumContains(enum []any, v any) bool {
+	if n, ok := v.(json.Number); ok {
+		f, err := n.Float64()
+		if err != nil {
+			return false
+		}
+		v = f
+	}
+
+	for _, e := range enum {
+		switch e := e.(type) {
+		case int64:
+			if f, ok := v.(float64); ok && f == float64(e) {
+				return true
+			}
+		default:
+			if e == v {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func jsonTypeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case json.Number, float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return fmt.Sprintf("%T", v)
+}
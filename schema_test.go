@@ -0,0 +1,170 @@
+package together
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type schemaTestAddress struct {
+	City string `json:"city" jsonschema:"description=City name\\, or town"`
+}
+
+type schemaTestBase struct {
+	ID int `json:"id"`
+}
+
+type schemaTestPerson struct {
+	schemaTestBase
+	Name      string             `json:"name" jsonschema:"description=Full name"`
+	Nickname  string             `json:"nickname,omitempty"`
+	Unit      string             `json:"unit,omitempty" jsonschema:"enum=celsius|fahrenheit,required"`
+	Rank      int                `json:"rank" jsonschema:"enum=1|2|3"`
+	Born      time.Time          `json:"born"`
+	Tags      []string           `json:"tags"`
+	Address   *schemaTestAddress `json:"address"`
+	Scores    map[string]float64 `json:"scores"`
+	Extra     json.RawMessage    `json:"extra,omitempty"`
+	Ignored   string             `json:"-"`
+	unexposed string
+}
+
+type schemaTestNode struct {
+	Children []schemaTestNode `json:"children"`
+}
+
+func TestGenerateSchema(t *testing.T) {
+	schema, err := SchemaFor[schemaTestPerson]()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"id":       {Type: "integer"},
+			"name":     {Type: "string", Description: "Full name"},
+			"nickname": {Type: "string"},
+			"unit":     {Type: "string", Enum: []any{"celsius", "fahrenheit"}},
+			"rank":     {Type: "integer", Enum: []any{int64(1), int64(2), int64(3)}},
+			"born":     {Type: "string", Format: "date-time"},
+			"tags":     {Type: "array", Nullable: true, Items: &Schema{Type: "string"}},
+			"address": {
+				Type:                 "object",
+				Nullable:             true,
+				Properties:           map[string]*Schema{"city": {Type: "string", Description: "City name, or town"}},
+				Required:             []string{"city"},
+				AdditionalProperties: false,
+			},
+			"scores": {Type: "object", Nullable: true, AdditionalProperties: &Schema{Type: "number"}},
+			"extra":  {},
+		},
+		Required:             []string{"id", "name", "unit", "rank", "born", "tags", "scores"},
+		AdditionalProperties: false,
+	}
+	if !reflect.DeepEqual(schema, want) {
+		got, _ := json.Marshal(schema)
+		exp, _ := json.Marshal(want)
+		t.Errorf("Result was incorrect, got: %s, want: %s.", got, exp)
+	}
+
+	// Case: Recursive and unsupported types are rejected
+	if _, err := SchemaFor[schemaTestNode](); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "recursive type")
+	}
+	if _, err := GenerateSchema(make(chan int)); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "unsupported type")
+	}
+	if _, err := GenerateSchema(struct {
+		A string `jsonschema:"bogus"`
+	}{}); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "unknown jsonschema keyword")
+	}
+}
+
+func TestNewFunctionTool(t *testing.T) {
+	tool, err := NewFunctionTool[schemaTestAddress]("lookup", "Look up an address")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if tool.Type != "function" || tool.Function.Name != "lookup" || tool.Function.Description != "Look up an address" {
+		t.Errorf("Result was incorrect, got: %+v.", tool)
+	}
+	want := `{"type":"object","properties":{"city":{"type":"string","description":"City name, or town"}},"required":["city"],"additionalProperties":false}`
+	if string(tool.Function.Parameters) != want {
+		t.Errorf("Result was incorrect, got: %s, want: %s.", tool.Function.Parameters, want)
+	}
+
+	// Case: Parameters must be an object
+	if _, err := NewFunctionTool[string]("a", "b"); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "must be a struct or map")
+	}
+
+	format, err := NewResponseFormat[schemaTestAddress]()
+	if err != nil || format.Type != ResponseFormatJSONObject || string(format.Schema) != want {
+		t.Errorf("Result was incorrect, got: %+v %v.", format, err)
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	schema, _ := SchemaFor[schemaTestPerson]()
+
+	valid := `{"id":1,"name":"a","unit":"celsius","rank":2,"born":"2024-01-02T03:04:05Z","tags":["x"],"address":{"city":"Oslo"},"scores":{"x":1.5}}`
+	cases := map[string]string{
+		valid:      "",
+		`[]`:       "$: expected object, got array",
+		`{"id":1}`: `$: missing required property "name"`,
+		strings.Replace(valid, `"id":1`, `"id":1.5`, 1):             "$.id: expected integer, got 1.5",
+		strings.Replace(valid, `"celsius"`, `"kelvin"`, 1):          "$.unit: value kelvin is not one of [celsius fahrenheit]",
+		strings.Replace(valid, `"rank":2`, `"rank":4`, 1):           "$.rank: value 4 is not one of [1 2 3]",
+		strings.Replace(valid, `"2024-01-02T03:04:05Z"`, `"x"`, 1):  `$.born: expected RFC 3339 date-time, got "x"`,
+		strings.Replace(valid, `["x"]`, `[1]`, 1):                   "$.tags[0]: expected string, got number",
+		strings.Replace(valid, `"Oslo"}`, `"Oslo","zip":1}`, 1):     `$.address: unexpected property "zip"`,
+		strings.Replace(valid, `{"x":1.5}`, `{"x":true}`, 1):        "$.scores.x: expected number, got boolean",
+		strings.Replace(valid, `["x"]`, `null`, 1):                  "",
+		strings.Replace(valid, `{"city":"Oslo"}`, `null`, 1):        "",
+		strings.Replace(valid, `,"address":{"city":"Oslo"}`, ``, 1): "",
+		strings.Replace(valid, `"name":"a"`, `"name":null`, 1):      "$.name: expected string, got null",
+	}
+
+	for input, want := range cases {
+		decoder := json.NewDecoder(strings.NewReader(input))
+		decoder.UseNumber()
+		var v any
+		_ = decoder.Decode(&v)
+
+		err := schema.Validate(v)
+		got := ""
+		if err != nil {
+			got = err.Error()
+		}
+		if got != want {
+			t.Errorf("Result was incorrect for %s, got: %s, want: %s.", input, got, want)
+		}
+	}
+
+	// Case: The encoding of a zero value is valid
+	type optional struct {
+		Name  *string        `json:"name"`
+		Tags  []string       `json:"tags"`
+		Codes map[string]int `json:"codes"`
+		Count int            `json:"count"`
+		Owner *struct {
+			Id string `json:"id" jsonschema:"required"`
+		} `json:"owner" jsonschema:"required"`
+	}
+	schema, _ = SchemaFor[optional]()
+	if !reflect.DeepEqual(schema.Required, []string{"tags", "codes", "count", "owner"}) {
+		t.Errorf("Result was incorrect, got: %v.", schema.Required)
+	}
+	data, _ := json.Marshal(optional{})
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	var v any
+	_ = decoder.Decode(&v)
+	if err := schema.Validate(v); err != nil {
+		t.Errorf("Error was incorrect for %s, got: %v, want: %v.", data, err, nil)
+	}
+}
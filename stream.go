@@ -0,0 +1,143 @@
+package together
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const streamDone = "[DONE]"
+
+// Stream reads Server-Sent Events from a streaming endpoint and decodes each
+// event payload into T. A Stream must be closed once the caller is finished
+// with it.
+type Stream[T any] struct {
+	ctx    context.Context
+	body   io.ReadCloser
+	reader *bufio.Reader
+	done   bool
+}
+
+func newStream[T any](ctx context.Context, body io.ReadCloser) *Stream[T] {
+	return &Stream[T]{
+		ctx:    ctx,
+		body:   body,
+		reader: bufio.NewReader(body),
+	}
+}
+
+// Recv blocks until the next event is available and returns it. Once the
+// server signals the end of the stream, Recv returns io.EOF. If the body ends
+// before the server signalled the end, e.g. because the connection was closed
+// mid-generation, Recv returns io.ErrUnexpectedEOF.
+func (s *Stream[T]) Recv() (T, error) {
+	var event T
+
+	if s.done {
+		return event, io.EOF
+	}
+
+	data, err := s.next()
+	if err != nil {
+		s.done = true
+		if ctxErr := s.ctx.Err(); ctxErr != nil {
+			return event, ctxErr
+		}
+		return event, err
+	}
+
+	if errPayload := decodeStreamError(data); errPayload != nil {
+		s.done = true
+		return event, errPayload
+	}
+
+	err = json.Unmarshal(data, &event)
+	if err != nil {
+		return event, err
+	}
+
+	return event, nil
+}
+
+// Close releases the underlying HTTP response body. It is safe to call Close
+// before the stream has been fully consumed.
+func (s *Stream[T]) Close() error {
+	s.done = true
+	return s.body.Close()
+}
+
+// next returns the data of the next non-empty event, joining multi-line data
+// fields with a newline as described by the SSE specification.
+func (s *Stream[T]) next() ([]byte, error) {
+	var data [][]byte
+
+	for {
+		if err := s.ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		line, err := s.reader.ReadBytes('\n')
+		line = bytes.TrimRight(line, "\r\n")
+
+		switch {
+		case len(line) == 0:
+			// A blank line dispatches the event.
+			if len(data) > 0 {
+				return s.dispatch(data)
+			}
+		case line[0] == ':':
+			// Lines beginning with a colon are comments, commonly used as keep-alives.
+		default:
+			field, value, _ := bytes.Cut(line, []byte(":"))
+			if string(field) == "data" {
+				data = append(data, bytes.TrimPrefix(value, []byte(" ")))
+			}
+		}
+
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				// The last event may lack its trailing blank line; the stream
+				// itself is only complete if it ended with [DONE].
+				if len(data) > 0 {
+					return s.dispatch(data)
+				}
+				return nil, io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+	}
+}
+
+// dispatch joins the data fields of an event. The [DONE] marker is the only
+// way a stream ends with io.EOF; next is not called again after it.
+func (s *Stream[T]) dispatch(data [][]byte) ([]byte, error) {
+	joined := bytes.Join(data, []byte("\n"))
+	if string(joined) == streamDone {
+		return nil, io.EOF
+	}
+	return joined, nil
+}
+
+// decodeStreamError returns an error if the event payload is an error object
+// sent in place of a regular chunk.
+func decodeStreamError(data []byte) error {
+	var payload struct {
+		Error json.RawMessage `json:"error"`
+	}
+	if json.Unmarshal(data, &payload) != nil || len(payload.Error) == 0 || string(payload.Error) == "null" {
+		return nil
+	}
+	apiErr := &APIError{Body: data}
+	apiErr.decode(data)
+	return apiErr
+}
+
+// isEventStream reports whether the response body is a Server-Sent Events stream.
+func isEventStream(res *http.Response) bool {
+	return strings.HasPrefix(res.Header.Get("Content-Type"), "text/event-stream")
+}
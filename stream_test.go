@@ -0,0 +1,73 @@
+package together
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// Test decoding events from a Server-Sent Events stream.
+func TestStream(t *testing.T) {
+	type event struct {
+		Text string `json:"text"`
+	}
+
+	// Case: Multi-line data fields are joined and the stream ends without [DONE]
+	body := io.NopCloser(strings.NewReader("data: {\"text\":\ndata: \"a\"}\r\n\r\nevent: ignored\ndata: {\"text\":\"b\"}"))
+	stream := newStream[event](context.Background(), body)
+
+	e, err := stream.Recv()
+	if err != nil || e.Text != "a" {
+		t.Errorf("Result was incorrect, got: %v %v, want: %s.", e, err, "a")
+	}
+	e, err = stream.Recv()
+	if err != nil || e.Text != "b" {
+		t.Errorf("Result was incorrect, got: %v %v, want: %s.", e, err, "b")
+	}
+	_, err = stream.Recv()
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, io.ErrUnexpectedEOF)
+	}
+	stream.Close()
+
+	// Case: A stream that ends with [DONE] returns io.EOF
+	body = io.NopCloser(strings.NewReader("data: {\"text\":\"a\"}\n\ndata: [DONE]"))
+	stream = newStream[event](context.Background(), body)
+
+	if e, err := stream.Recv(); err != nil || e.Text != "a" {
+		t.Errorf("Result was incorrect, got: %v %v, want: %s.", e, err, "a")
+	}
+	_, err = stream.Recv()
+	if err != io.EOF {
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, io.EOF)
+	}
+	stream.Close()
+
+	// Case: Error payloads are surfaced as errors
+	body = io.NopCloser(strings.NewReader("data: {\"error\":{\"message\":\"boom\"}}\n\n"))
+	stream = newStream[event](context.Background(), body)
+
+	_, err = stream.Recv()
+	if err == nil || errors.Is(err, io.EOF) {
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, "stream error")
+	}
+	_, err = stream.Recv()
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, io.EOF)
+	}
+	stream.Close()
+
+	// Case: Cancelled context stops the stream
+	ctx, cancel := context.WithCancel(context.Background())
+	body = io.NopCloser(strings.NewReader("data: {\"text\":\"a\"}\n\n"))
+	stream = newStream[event](ctx, body)
+	cancel()
+
+	_, err = stream.Recv()
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Error was incorrect, got: %s, want: %s.", err, context.Canceled)
+	}
+	stream.Close()
+}
@@ -0,0 +1,91 @@
+package together
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ChatCompletionsJSON requests a JSON response constrained to the schema of T,
+// validates the assistant output against that schema and decodes it into T.
+//
+// When the output is not valid, the model is shown the validation error and
+// asked again, up to retries additional times. The last response is returned
+// alongside the decoded value so that usage can be inspected.
+func ChatCompletionsJSON[T any](ctx context.Context, api *API, model string, messages []Message, request ChatCompletionsRequest, retries int) (T, ChatCompletionsResponse, error) {
+	var value T
+
+	if retries < 0 {
+		return value, ChatCompletionsResponse{}, fmt.Errorf("invalid retry count %d: must not be negative", retries)
+	}
+
+	schema, err := SchemaFor[T]()
+	if err != nil {
+		return value, ChatCompletionsResponse{}, err
+	}
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return value, ChatCompletionsResponse{}, err
+	}
+	request.ResponseFormat = ResponseFormatObject{Type: ResponseFormatJSONObject, Schema: raw}
+
+	transcript := append([]Message{}, messages...)
+
+	var resp ChatCompletionsResponse
+	var lastErr error
+	attempts := 0
+	for attempts <= retries {
+		resp, err = api.ChatCompletions(ctx, model, transcript, request)
+		if err != nil {
+			return value, resp, err
+		}
+		attempts++
+
+		content := resp.Content()
+		value, lastErr = decodeStructuredOutput[T](schema, content)
+		if lastErr == nil {
+			return value, resp, nil
+		}
+
+		transcript = append(transcript,
+			Message{Role: RoleAssistant, Content: content},
+			Message{Role: RoleUser, Content: fmt.Sprintf("The previous response was invalid: %s. Respond again with only a JSON value that matches the required schema.", lastErr)},
+		)
+	}
+
+	return value, resp, fmt.Errorf("structured output did not match the schema after %d attempt(s): %w", attempts, lastErr)
+}
+
+// decodeStructuredOutput validates content against schema and decodes it into T.
+// Markdown code fences around the JSON value are ignored.
+func decodeStructuredOutput[T any](schema *Schema, content string) (T, error) {
+	var value T
+
+	content = strings.TrimSpace(content)
+	if strings.HasPrefix(content, "```") {
+		content = strings.TrimPrefix(content, "```json")
+		content = strings.TrimPrefix(content, "```")
+		content = strings.TrimSuffix(content, "```")
+	}
+
+	decoder := json.NewDecoder(bytes.NewBufferString(content))
+	decoder.UseNumber()
+
+	var generic any
+	if err := decoder.Decode(&generic); err != nil {
+		return value, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if decoder.More() {
+		return value, fmt.Errorf("invalid JSON: unexpected data after the top-level value")
+	}
+	if err := schema.Validate(generic); err != nil {
+		return value, err
+	}
+
+	if err := json.Unmarshal([]byte(content), &value); err != nil {
+		return value, err
+	}
+	return value, nil
+}
@@ -0,0 +1,81 @@
+package together
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type structuredTestWeather struct {
+	City        string  `json:"city"`
+	Temperature float64 `json:"temperature"`
+}
+
+type structuredTestForecast struct {
+	City     string   `json:"city"`
+	Warnings []string `json:"warnings"`
+	Wind     *float64 `json:"wind"`
+}
+
+func TestChatCompletionsJSON(t *testing.T) {
+	// The server first returns output that is missing a property, then valid output.
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body ChatCompletionsRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		if body.ResponseFormat.Type != ResponseFormatJSONObject || len(body.ResponseFormat.Schema) == 0 {
+			t.Errorf("Result was incorrect, got: %+v.", body.ResponseFormat)
+		}
+
+		content := `{\"city\":\"Oslo\"}`
+		last := body.Messages[len(body.Messages)-1]
+		if strings.Contains(last.Content, `missing required property "temperature"`) {
+			content = "```json\n{\"city\":\"Oslo\",\"temperature\":4.5}\n```"
+			content = strings.ReplaceAll(strings.ReplaceAll(content, `"`, `\"`), "\n", `\n`)
+		}
+		fmt.Fprintf(w, `{"choices":[{"message":{"role":"assistant","content":"%s"}}]}`, content)
+	}))
+	defer ts.Close()
+
+	req, _ := New("hunter2", WithBaseURL(ts.URL), WithRetryMax(1))
+
+	// Case: Invalid output is retried with the validation error
+	weather, resp, err := ChatCompletionsJSON[structuredTestWeather](context.TODO(), req, "a", []Message{{Role: RoleUser, Content: "Weather?"}}, ChatCompletionsRequest{}, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if weather.City != "Oslo" || weather.Temperature != 4.5 {
+		t.Errorf("Result was incorrect, got: %+v.", weather)
+	}
+	if len(resp.Choices) != 1 {
+		t.Errorf("Result was incorrect, got: %d, want: %d.", len(resp.Choices), 1)
+	}
+
+	// Case: Fails once retries are exhausted
+	_, _, err = ChatCompletionsJSON[structuredTestWeather](context.TODO(), req, "a", []Message{{Role: RoleUser, Content: "Weather?"}}, ChatCompletionsRequest{}, 0)
+	if err == nil || !strings.Contains(err.Error(), "after 1 attempt(s)") {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "after 1 attempt(s)")
+	}
+
+	// Case: Fails with a negative retry count
+	_, _, err = ChatCompletionsJSON[structuredTestWeather](context.TODO(), req, "a", []Message{{Role: RoleUser, Content: "Weather?"}}, ChatCompletionsRequest{}, -1)
+	if err == nil || !strings.Contains(err.Error(), "must not be negative") {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "invalid retry count -1: must not be negative")
+	}
+
+	// Case: null is accepted for pointer and slice fields
+	ts2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"choices":[{"message":{"role":"assistant","content":"{\"city\":\"Oslo\",\"warnings\":null,\"wind\":null}"}}]}`)
+	}))
+	defer ts2.Close()
+
+	req, _ = New("hunter2", WithBaseURL(ts2.URL), WithRetryMax(1))
+	forecast, _, err := ChatCompletionsJSON[structuredTestForecast](context.TODO(), req, "a", []Message{{Role: RoleUser, Content: "Forecast?"}}, ChatCompletionsRequest{}, 0)
+	if err != nil || forecast.City != "Oslo" || forecast.Warnings != nil || forecast.Wind != nil {
+		t.Errorf("Result was incorrect, got: %+v, %v.", forecast, err)
+	}
+}
@@ -1,15 +1,18 @@
 package together
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"net/http/httputil"
+	"os"
 	"regexp"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
@@ -22,6 +25,8 @@ const (
 	defaultRetries  = 5
 	userAgent       = "together-go"
 
+	maxDebugBodySize = 64 << 10
+
 	errEmptyAPIToken = "invalid credentials: API Token must not be empty" //nolint:gosec,unused
 )
 
@@ -36,9 +41,19 @@ type API struct {
 	headers   http.Header
 	Client    *retryablehttp.Client
 	Debug     bool
+	logger    Logger
+	limiter   *limiter
+	timeout   *time.Duration // Set by WithTimeout and applied once all options have run.
+
+	embeddingCache EmbeddingCache
+
+	rateLimitMu sync.Mutex
+	rateLimit   *RateLimit
 }
 
-func New(key string) (*API, error) {
+// New creates a new API client for the given API key. Options are applied in
+// the order they are given, after the defaults have been set.
+func New(key string, opts ...Option) (*API, error) {
 	if key == "" {
 		return nil, errors.New(errEmptyAPIToken)
 	}
@@ -47,18 +62,39 @@ func New(key string) (*API, error) {
 	api.BaseURL = fmt.Sprintf("%s://%s", defaultScheme, defaultHostname)
 	api.Client = retryablehttp.NewClient()
 	api.Client.RetryMax = defaultRetries
+	api.Client.ErrorHandler = passthroughResponse
+	api.Client.CheckRetry = checkRetry
+	api.Client.Backoff = backoff
 	api.Debug = false
 	api.APIKey = key
 	api.UserAgent = userAgent + "/" + Version + " (" + strconv.FormatInt(time.Now().UnixNano(), 36) + ")"
+	api.headers = make(http.Header)
+	api.logger = log.New(os.Stderr, "", log.LstdFlags)
+
+	for _, opt := range opts {
+		if err := opt(api); err != nil {
+			return nil, err
+		}
+	}
+
+	if api.timeout != nil {
+		client := *api.Client.HTTPClient
+		client.Timeout = *api.timeout
+		api.Client.HTTPClient = &client
+	}
 
 	return api, nil
 }
 
-func (api *API) request(ctx context.Context, method, uri string, reqBody io.Reader, headers http.Header) (*http.Response, error) {
+// request sends an API request. reqBody is passed to retryablehttp, so it may be
+// a retryablehttp.ReaderFunc for bodies that should be streamed rather than
+// buffered for retries.
+func (api *API) request(ctx context.Context, method, uri string, reqBody interface{}, headers http.Header) (*http.Response, error) {
 	req, err := retryablehttp.NewRequestWithContext(ctx, method, api.BaseURL+uri, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("HTTP request creation failed: %w", err)
 	}
+	req = req.WithContext(context.WithValue(ctx, methodContextKey{}, method))
 
 	combinedHeaders := make(http.Header)
 	copyHeader(combinedHeaders, api.headers)
@@ -89,26 +125,69 @@ func (api *API) request(ctx context.Context, method, uri string, reqBody io.Read
 				dump = valueRegex.ReplaceAll(dump, []byte("[redacted]"))
 			}
 		}
-		log.Printf("\n%s", string(dump))
+		api.logf("\n%s", string(dump))
+	}
+
+	var estimate int
+	if api.limiter != nil {
+		if buf, ok := reqBody.(*bytes.Buffer); ok {
+			estimate = estimateRequestTokens(buf.Bytes())
+		}
+		if err := api.limiter.wait(ctx, estimate); err != nil {
+			return nil, err
+		}
 	}
 
 	resp, err := api.Client.Do(req)
 	if err != nil {
+		if api.limiter != nil {
+			api.limiter.reconcile(estimate, 0)
+		}
 		return nil, fmt.Errorf("HTTP request failed: %w", err)
 	}
+	api.recordRateLimit(resp.Header)
+
+	if api.limiter != nil {
+		if err := api.limiter.reconcileResponse(resp, estimate); err != nil {
+			return nil, err
+		}
+	}
 
 	if api.Debug {
-		dump, err := httputil.DumpResponse(resp, true)
+		dump, err := httputil.DumpResponse(resp, dumpableBody(resp))
 		if err != nil {
 			return resp, err
 		}
-		log.Printf("\n%s", string(dump))
+		api.logf("\n%s", string(dump))
 	}
 
 	return resp, nil
 
 }
 
+// dumpableBody reports whether a response body may be included in debug
+// output. Dumping reads the whole body, which would block on an event stream
+// and buffer file downloads in memory.
+func dumpableBody(resp *http.Response) bool {
+	if isEventStream(resp) {
+		return false
+	}
+	if resp.ContentLength >= 0 {
+		return resp.ContentLength <= maxDebugBodySize
+	}
+	return strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json")
+}
+
+// logf writes to the configured logger, falling back to the standard logger
+// for clients that were not created with New.
+func (api *API) logf(format string, v ...interface{}) {
+	if api.logger == nil {
+		log.Printf(format, v...)
+		return
+	}
+	api.logger.Printf(format, v...)
+}
+
 // copyHeader copies all headers for `source` and sets them on `target`.
 // based on https://godoc.org/github.com/golang/gddo/httputil/header#Copy
 func copyHeader(target, source http.Header) {
@@ -116,3 +195,13 @@ func copyHeader(target, source http.Header) {
 		target[k] = vs
 	}
 }
+
+// passthroughResponse returns the last response once retries are exhausted so
+// that callers can decode the API error from its body rather than receiving
+// a generic "giving up" error.
+func passthroughResponse(resp *http.Response, err error, _ int) (*http.Response, error) {
+	if resp != nil {
+		return resp, nil
+	}
+	return nil, err
+}
@@ -0,0 +1,173 @@
+package together
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const defaultMaxIterations = 10
+
+// ErrMaxIterations is returned by ToolRunner.Run when the model is still
+// requesting tool calls after the maximum number of iterations.
+var ErrMaxIterations = errors.New("tool runner: maximum iterations reached")
+
+// ToolHandler executes a tool call with the JSON arguments generated by the
+// model. The returned string is sent back to the model as the tool result.
+type ToolHandler func(ctx context.Context, arguments json.RawMessage) (string, error)
+
+// ToolRunner runs a function-calling conversation, dispatching the tool calls
+// requested by the model to registered Go handlers until the model returns a
+// final answer.
+type ToolRunner struct {
+	Request       ChatCompletionsRequest // Base request sent on every iteration.
+	MaxIterations int                    // Maximum number of chat completions; defaults to 10.
+	Parallel      bool                   // Execute the tool calls of a single response concurrently.
+
+	api      *API
+	model    string
+	tools    []Tool
+	handlers map[string]ToolHandler
+}
+
+type ToolRunResult struct {
+	Messages   []Message               // Full transcript, including the input messages.
+	Response   ChatCompletionsResponse // Last response returned by the model.
+	Usage      UsageObject             // Usage aggregated across all iterations.
+	Iterations int
+}
+
+// NewToolRunner creates a ToolRunner for the given model.
+func (api *API) NewToolRunner(model string) *ToolRunner {
+	return &ToolRunner{
+		api:      api,
+		model:    model,
+		handlers: make(map[string]ToolHandler),
+	}
+}
+
+// Register declares a function tool to the model and the handler that executes it.
+// Registering a function with the same name again replaces its handler.
+func (r *ToolRunner) Register(function FunctionObject, handler ToolHandler) error {
+	if function.Name == "" {
+		return fmt.Errorf("no function name provided")
+	}
+	if handler == nil {
+		return fmt.Errorf("no handler provided for function %q", function.Name)
+	}
+
+	if _, ok := r.handlers[function.Name]; !ok {
+		r.tools = append(r.tools, Tool{Type: "function", Function: function})
+	}
+	r.handlers[function.Name] = handler
+	return nil
+}
+
+// Run sends the conversation to the model and executes the requested tool calls,
+// feeding their results back until the model responds without tool calls.
+//
+// Handler errors and calls to unknown tools are reported back to the model
+// rather than aborting the run. If the iteration limit is reached, the partial
+// result is returned together with ErrMaxIterations.
+func (r *ToolRunner) Run(ctx context.Context, messages []Message) (ToolRunResult, error) {
+	if ctx == nil {
+		return ToolRunResult{}, fmt.Errorf("no context provided")
+	}
+
+	maxIterations := r.MaxIterations
+	if maxIterations <= 0 {
+		maxIterations = defaultMaxIterations
+	}
+
+	request := r.Request
+	request.Tools = append(append([]Tool{}, request.Tools...), r.tools...)
+
+	result := ToolRunResult{Messages: append([]Message{}, messages...)}
+	for result.Iterations < maxIterations {
+		resp, err := r.api.ChatCompletions(ctx, r.model, result.Messages, request)
+		if err != nil {
+			return result, err
+		}
+		result.Iterations++
+		result.Response = resp
+		result.Usage.PromptTokens += resp.Usage.PromptTokens
+		result.Usage.CompletionTokens += resp.Usage.CompletionTokens
+		result.Usage.TotalTokens += resp.Usage.TotalTokens
+
+		choice, ok := resp.FirstChoice()
+		if !ok {
+			return result, fmt.Errorf("no choices returned")
+		}
+		if choice.Message.Role == "" {
+			choice.Message.Role = RoleAssistant
+		}
+		result.Messages = append(result.Messages, choice.Message)
+
+		if len(choice.Message.ToolCalls) == 0 {
+			return result, nil
+		}
+
+		replies, err := r.execute(ctx, choice.Message.ToolCalls)
+		if err != nil {
+			return result, err
+		}
+		result.Messages = append(result.Messages, replies...)
+	}
+
+	return result, ErrMaxIterations
+}
+
+// execute runs the tool calls and returns the tool messages in call order.
+func (r *ToolRunner) execute(ctx context.Context, calls []ToolCall) ([]Message, error) {
+	replies := make([]Message, len(calls))
+
+	if r.Parallel {
+		var wg sync.WaitGroup
+		for i, call := range calls {
+			wg.Add(1)
+			go func(i int, call ToolCall) {
+				defer wg.Done()
+				replies[i] = r.call(ctx, call)
+			}(i, call)
+		}
+		wg.Wait()
+	} else {
+		for i, call := range calls {
+			replies[i] = r.call(ctx, call)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return replies, nil
+}
+
+func (r *ToolRunner) call(ctx context.Context, call ToolCall) Message {
+	reply := Message{
+		Role:       RoleTool,
+		ToolCallID: call.Id,
+		Name:       call.Function.Name,
+	}
+
+	handler, ok := r.handlers[call.Function.Name]
+	if !ok {
+		reply.Content = fmt.Sprintf("error: unknown tool %q", call.Function.Name)
+		return reply
+	}
+
+	arguments := json.RawMessage(call.Function.Arguments)
+	if len(arguments) == 0 {
+		arguments = json.RawMessage("{}")
+	}
+
+	content, err := handler(ctx, arguments)
+	if err != nil {
+		reply.Content = "error: " + err.Error()
+		return reply
+	}
+	reply.Content = content
+	return reply
+}
@@ -0,0 +1,87 @@
+package together
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestToolRunner(t *testing.T) {
+	// The server requests two tool calls until it has seen their results.
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body ChatCompletionsRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		if len(body.Tools) != 1 || body.Tools[0].Function.Name != "add" {
+			t.Errorf("Result was incorrect, got: %v, want: %s.", body.Tools, "add")
+		}
+
+		last := body.Messages[len(body.Messages)-1]
+		if last.Role == RoleTool {
+			fmt.Fprintln(w, `{"choices":[{"message":{"role":"assistant","content":"done"},"finish_reason":"stop"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`)
+			return
+		}
+		fmt.Fprintln(w, `{"choices":[{"message":{"role":"assistant","tool_calls":[{"id":"1","type":"function","function":{"name":"add","arguments":"{\"a\":1,\"b\":2}"}},{"id":"2","type":"function","function":{"name":"missing","arguments":"{}"}}]},"finish_reason":"tool_calls"}],"usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`)
+	}))
+	defer ts.Close()
+
+	req, _ := New("hunter2", WithBaseURL(ts.URL), WithRetryMax(1))
+
+	runner := req.NewToolRunner("a")
+	runner.Parallel = true
+
+	// Case: Register fails without a name or handler
+	if err := runner.Register(FunctionObject{}, nil); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no function name provided")
+	}
+	if err := runner.Register(FunctionObject{Name: "add"}, nil); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "no handler provided")
+	}
+
+	err := runner.Register(FunctionObject{Name: "add"}, func(ctx context.Context, arguments json.RawMessage) (string, error) {
+		var args struct{ A, B int }
+		if err := json.Unmarshal(arguments, &args); err != nil {
+			return "", err
+		}
+		return fmt.Sprint(args.A + args.B), nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Case: Tool calls are executed and the final answer is returned
+	result, err := runner.Run(context.TODO(), []Message{{Role: RoleUser, Content: "1+2?"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.Iterations != 2 || result.Usage.TotalTokens != 4 {
+		t.Errorf("Result was incorrect, got: %d iterations and %d tokens, want: %d and %d.", result.Iterations, result.Usage.TotalTokens, 2, 4)
+	}
+	if len(result.Messages) != 5 {
+		t.Fatalf("Result was incorrect, got: %d messages, want: %d.", len(result.Messages), 5)
+	}
+	if result.Messages[2].Content != "3" || result.Messages[2].ToolCallID != "1" {
+		t.Errorf("Result was incorrect, got: %+v.", result.Messages[2])
+	}
+	if result.Messages[3].Content != `error: unknown tool "missing"` {
+		t.Errorf("Result was incorrect, got: %s.", result.Messages[3].Content)
+	}
+	if result.Response.Content() != "done" {
+		t.Errorf("Result was incorrect, got: %s, want: %s.", result.Response.Content(), "done")
+	}
+
+	// Case: Iteration limit is reached
+	runner.MaxIterations = 1
+	result, err = runner.Run(context.TODO(), []Message{{Role: RoleUser, Content: "1+2?"}})
+	if !errors.Is(err, ErrMaxIterations) {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, ErrMaxIterations)
+	}
+	if result.Iterations != 1 {
+		t.Errorf("Result was incorrect, got: %d, want: %d.", result.Iterations, 1)
+	}
+}
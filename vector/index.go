@@ -0,0 +1,394 @@
+package vector
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+const (
+	indexMagic   = "TGVI"
+	indexVersion = 1
+
+	// Upper bounds used to reject corrupt files before allocating.
+	maxIndexDim     = 1 << 20
+	maxStringLength = 1 << 24
+)
+
+// Item is a vector stored in an index.
+type Item struct {
+	ID       string
+	Vector   []float32
+	Metadata map[string]string
+}
+
+// Result is a single search match. For MetricEuclidean the score is the
+// distance, so lower scores are better; for the other metrics higher is better.
+type Result struct {
+	ID       string
+	Score    float32
+	Metadata map[string]string
+}
+
+// FlatIndex is an in-memory index that compares a query against every stored
+// vector. It is safe for concurrent use.
+type FlatIndex struct {
+	mu     sync.RWMutex
+	dim    int
+	metric Metric
+	items  []Item
+	norms  []float32
+	ids    map[string]int
+}
+
+// NewFlatIndex creates an empty index for vectors with dim dimensions.
+func NewFlatIndex(dim int, metric Metric) (*FlatIndex, error) {
+	if dim <= 0 {
+		return nil, errors.New("dimension must be greater than 0")
+	}
+	if metric > MetricEuclidean {
+		return nil, fmt.Errorf("unsupported metric %s", metric)
+	}
+
+	return &FlatIndex{
+		dim:    dim,
+		metric: metric,
+		ids:    make(map[string]int),
+	}, nil
+}
+
+// Dim returns the number of dimensions of the stored vectors.
+func (x *FlatIndex) Dim() int {
+	return x.dim
+}
+
+// Metric returns the metric used to compare vectors.
+func (x *FlatIndex) Metric() Metric {
+	return x.metric
+}
+
+// Len returns the number of stored items.
+func (x *FlatIndex) Len() int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return len(x.items)
+}
+
+// Add stores a vector under id, replacing any existing item with the same id.
+// The vector and metadata are copied.
+func (x *FlatIndex) Add(id string, vector []float32, metadata map[string]string) error {
+	if len(vector) != x.dim {
+		return fmt.Errorf("vector has %d dimensions, want %d", len(vector), x.dim)
+	}
+
+	item := Item{ID: id, Vector: append([]float32(nil), vector...), Metadata: cloneMetadata(metadata)}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.put(item)
+	return nil
+}
+
+func (x *FlatIndex) put(item Item) {
+	if i, ok := x.ids[item.ID]; ok {
+		x.items[i] = item
+		x.norms[i] = Norm(item.Vector)
+		return
+	}
+	x.ids[item.ID] = len(x.items)
+	x.items = append(x.items, item)
+	x.norms = append(x.norms, Norm(item.Vector))
+}
+
+// Remove deletes the item with the given id and reports whether it existed.
+func (x *FlatIndex) Remove(id string) bool {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	i, ok := x.ids[id]
+	if !ok {
+		return false
+	}
+
+	last := len(x.items) - 1
+	x.items[i], x.norms[i] = x.items[last], x.norms[last]
+	x.ids[x.items[i].ID] = i
+	x.items, x.norms = x.items[:last], x.norms[:last]
+	delete(x.ids, id)
+	return true
+}
+
+// Get returns a copy of the item with the given id.
+func (x *FlatIndex) Get(id string) (Item, bool) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	i, ok := x.ids[id]
+	if !ok {
+		return Item{}, false
+	}
+	item := x.items[i]
+	return Item{ID: item.ID, Vector: append([]float32(nil), item.Vector...), Metadata: cloneMetadata(item.Metadata)}, true
+}
+
+// cloneMetadata copies metadata so that callers never share a map with the
+// index. Empty metadata is returned as nil.
+func cloneMetadata(metadata map[string]string) map[string]string {
+	if len(metadata) == 0 {
+		return nil
+	}
+	clone := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		clone[k] = v
+	}
+	return clone
+}
+
+// Search returns up to k items most similar to query, best first. If filter is
+// not nil, only items for which it returns true are considered. The items
+// passed to filter are shared with the index and must not be modified.
+func (x *FlatIndex) Search(query []float32, k int, filter func(Item) bool) ([]Result, error) {
+	if len(query) != x.dim {
+		return nil, fmt.Errorf("query has %d dimensions, want %d", len(query), x.dim)
+	}
+	if k <= 0 {
+		return nil, nil
+	}
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	queryNorm := Norm(query)
+	h := &resultHeap{lowerIsBetter: x.metric == MetricEuclidean}
+	for i, item := range x.items {
+		if filter != nil && !filter(item) {
+			continue
+		}
+
+		var score float32
+		switch x.metric {
+		case MetricCosine:
+			if queryNorm != 0 && x.norms[i] != 0 {
+				score = Dot(query, item.Vector) / (queryNorm * x.norms[i])
+			}
+		case MetricDot:
+			score = Dot(query, item.Vector)
+		case MetricEuclidean:
+			score = Euclidean(query, item.Vector)
+		}
+
+		result := Result{ID: item.ID, Score: score, Metadata: item.Metadata}
+		if h.Len() < k {
+			heap.Push(h, result)
+		} else if h.better(result, h.results[0]) {
+			h.results[0] = result
+			heap.Fix(h, 0)
+		}
+	}
+
+	results := make([]Result, h.Len())
+	for i := len(results) - 1; i >= 0; i-- {
+		results[i] = heap.Pop(h).(Result)
+		results[i].Metadata = cloneMetadata(results[i].Metadata)
+	}
+	return results, nil
+}
+
+// resultHeap keeps the worst of the current top results at its root.
+type resultHeap struct {
+	results       []Result
+	lowerIsBetter bool
+}
+
+func (h *resultHeap) better(a, b Result) bool {
+	if h.lowerIsBetter {
+		return a.Score < b.Score
+	}
+	return a.Score > b.Score
+}
+
+func (h *resultHeap) Len() int           { return len(h.results) }
+func (h *resultHeap) Less(i, j int) bool { return h.better(h.results[j], h.results[i]) }
+func (h *resultHeap) Swap(i, j int)      { h.results[i], h.results[j] = h.results[j], h.results[i] }
+func (h *resultHeap) Push(v any)         { h.results = append(h.results, v.(Result)) }
+func (h *resultHeap) Pop() any {
+	last := h.results[len(h.results)-1]
+	h.results = h.results[:len(h.results)-1]
+	return last
+}
+
+// Save writes the index to w in a compact little-endian binary format.
+func (x *FlatIndex) Save(w io.Writer) error {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString(indexMagic)
+	bw.WriteByte(indexVersion)
+	bw.WriteByte(byte(x.metric))
+	writeUvarint(bw, uint64(x.dim))
+	writeUvarint(bw, uint64(len(x.items)))
+
+	buf := make([]byte, 4)
+	for _, item := range x.items {
+		writeString(bw, item.ID)
+
+		keys := make([]string, 0, len(item.Metadata))
+		for k := range item.Metadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		writeUvarint(bw, uint64(len(keys)))
+		for _, k := range keys {
+			writeString(bw, k)
+			writeString(bw, item.Metadata[k])
+		}
+
+		for _, v := range item.Vector {
+			binary.LittleEndian.PutUint32(buf, math.Float32bits(v))
+			bw.Write(buf)
+		}
+	}
+
+	return bw.Flush()
+}
+
+// Load reads an index written by Save.
+func Load(r io.Reader) (*FlatIndex, error) {
+	br := bufio.NewReader(r)
+
+	header := make([]byte, len(indexMagic)+2)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, fmt.Errorf("reading index header: %w", err)
+	}
+	if string(header[:len(indexMagic)]) != indexMagic {
+		return nil, errors.New("not a vector index file")
+	}
+	if version := header[len(indexMagic)]; version != indexVersion {
+		return nil, fmt.Errorf("unsupported index version %d", version)
+	}
+
+	dim, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("reading index header: %w", err)
+	}
+	count, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("reading index header: %w", err)
+	}
+
+	if dim > maxIndexDim {
+		return nil, fmt.Errorf("invalid index dimension %d", dim)
+	}
+
+	x, err := NewFlatIndex(int(dim), Metric(header[len(indexMagic)+1]))
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 4*dim)
+	for i := uint64(0); i < count; i++ {
+		item := Item{}
+		if item.ID, err = readString(br); err != nil {
+			return nil, fmt.Errorf("reading item %d: %w", i, err)
+		}
+
+		n, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, fmt.Errorf("reading item %d: %w", i, err)
+		}
+		if n > 0 {
+			item.Metadata = make(map[string]string)
+		}
+		for j := uint64(0); j < n; j++ {
+			k, err := readString(br)
+			if err != nil {
+				return nil, fmt.Errorf("reading item %d: %w", i, err)
+			}
+			v, err := readString(br)
+			if err != nil {
+				return nil, fmt.Errorf("reading item %d: %w", i, err)
+			}
+			item.Metadata[k] = v
+		}
+
+		if _, err := io.ReadFull(br, buf); err != nil {
+			return nil, fmt.Errorf("reading item %d: %w", i, err)
+		}
+		item.Vector = make([]float32, dim)
+		for j := range item.Vector {
+			item.Vector[j] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*j:]))
+		}
+
+		x.put(item)
+	}
+
+	return x, nil
+}
+
+// SaveFile writes the index to path with 0644 permissions. The file is replaced
+// atomically.
+func (x *FlatIndex) SaveFile(path string) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := x.Save(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(0o644); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// LoadFile reads an index written by SaveFile.
+func LoadFile(path string) (*FlatIndex, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Load(f)
+}
+
+func writeUvarint(w *bufio.Writer, v uint64) {
+	buf := make([]byte, binary.MaxVarintLen64)
+	w.Write(buf[:binary.PutUvarint(buf, v)])
+}
+
+func writeString(w *bufio.Writer, s string) {
+	writeUvarint(w, uint64(len(s)))
+	w.WriteString(s)
+}
+
+func readString(r *bufio.Reader) (string, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return "", err
+	}
+	if n > maxStringLength {
+		return "", fmt.Errorf("invalid string length %d", n)
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
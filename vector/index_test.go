@@ -0,0 +1,126 @@
+package vector
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFlatIndex(t *testing.T) {
+	// Case: Invalid configuration
+	if _, err := NewFlatIndex(0, MetricCosine); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "dimension must be greater than 0")
+	}
+
+	x, err := NewFlatIndex(2, MetricCosine)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Case: Dimension mismatch
+	if err := x.Add("bad", []float32{1}, nil); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "vector has 1 dimensions, want 2")
+	}
+
+	x.Add("a", []float32{1, 0}, map[string]string{"lang": "en"})
+	x.Add("b", []float32{0, 1}, map[string]string{"lang": "no"})
+	x.Add("c", []float32{1, 1}, map[string]string{"lang": "en"})
+	x.Add("c", []float32{1, 0.5}, map[string]string{"lang": "en"})
+
+	if x.Len() != 3 {
+		t.Errorf("Result was incorrect, got: %d, want: %d.", x.Len(), 3)
+	}
+
+	// Case: Top-k ordered best first
+	results, err := x.Search([]float32{1, 0}, 2, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(results) != 2 || results[0].ID != "a" || results[1].ID != "c" {
+		t.Errorf("Result was incorrect, got: %+v.", results)
+	}
+
+	// Case: Filters exclude items
+	results, _ = x.Search([]float32{1, 0}, 5, func(item Item) bool { return item.Metadata["lang"] == "no" })
+	if len(results) != 1 || results[0].ID != "b" {
+		t.Errorf("Result was incorrect, got: %+v.", results)
+	}
+
+	// Case: Remove
+	if !x.Remove("a") || x.Remove("a") {
+		t.Errorf("Result was incorrect, want: first remove to succeed and second to fail.")
+	}
+	if _, ok := x.Get("a"); ok {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", ok, false)
+	}
+	if item, ok := x.Get("c"); !ok || !reflect.DeepEqual(item.Vector, []float32{1, 0.5}) {
+		t.Errorf("Result was incorrect, got: %+v.", item)
+	}
+
+	// Case: Returned items and results are copies
+	item, _ := x.Get("c")
+	item.Vector[0], item.Metadata["lang"] = 100, "xx"
+	results, _ = x.Search([]float32{1, 0}, 1, nil)
+	results[0].Metadata["lang"] = "yy"
+	if item, _ := x.Get("c"); !reflect.DeepEqual(item, Item{ID: "c", Vector: []float32{1, 0.5}, Metadata: map[string]string{"lang": "en"}}) {
+		t.Errorf("Result was incorrect, got: %+v.", item)
+	}
+
+	// Case: Euclidean distance ranks lower scores first
+	e, _ := NewFlatIndex(1, MetricEuclidean)
+	e.Add("near", []float32{1}, nil)
+	e.Add("far", []float32{10}, nil)
+	results, _ = e.Search([]float32{0}, 1, nil)
+	if len(results) != 1 || results[0].ID != "near" || results[0].Score != 1 {
+		t.Errorf("Result was incorrect, got: %+v.", results)
+	}
+}
+
+func TestFlatIndexPersistence(t *testing.T) {
+	x, _ := NewFlatIndex(3, MetricDot)
+	x.Add("a", []float32{1, 2, 3}, map[string]string{"k": "v", "x": "y"})
+	x.Add("b", []float32{-1, 0.5, 0}, nil)
+
+	var buf bytes.Buffer
+	if err := x.Save(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	loaded, err := Load(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if loaded.Dim() != 3 || loaded.Metric() != MetricDot || loaded.Len() != 2 {
+		t.Errorf("Result was incorrect, got: %d %s %d.", loaded.Dim(), loaded.Metric(), loaded.Len())
+	}
+	for _, id := range []string{"a", "b"} {
+		want, _ := x.Get(id)
+		got, _ := loaded.Get(id)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Result was incorrect, got: %+v, want: %+v.", got, want)
+		}
+	}
+
+	// Case: Truncated and foreign data are rejected
+	if _, err := Load(bytes.NewReader(buf.Bytes()[:buf.Len()-1])); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "unexpected EOF")
+	}
+	if _, err := Load(bytes.NewReader([]byte("nope, not an index"))); err == nil {
+		t.Errorf("Error was incorrect, got: %v, want: %s.", err, "not a vector index file")
+	}
+
+	// Case: Files round trip
+	path := filepath.Join(t.TempDir(), "index.bin")
+	if err := x.SaveFile(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o644 {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", info.Mode().Perm(), os.FileMode(0o644))
+	}
+	loaded, err = LoadFile(path)
+	if err != nil || loaded.Len() != 2 {
+		t.Errorf("Result was incorrect, got: %v %v.", loaded, err)
+	}
+}
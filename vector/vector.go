@@ -0,0 +1,94 @@
+// Package vector provides similarity metrics and an in-memory index for
+// embeddings returned by the Together AI embeddings endpoint.
+package vector
+
+import (
+	"fmt"
+	"math"
+)
+
+// Metric selects how vectors are compared.
+type Metric uint8
+
+const (
+	MetricCosine    Metric = iota // Cosine similarity; higher is more similar.
+	MetricDot                     // Dot product; higher is more similar.
+	MetricEuclidean               // Euclidean distance; lower is more similar.
+)
+
+func (m Metric) String() string {
+	switch m {
+	case MetricCosine:
+		return "cosine"
+	case MetricDot:
+		return "dot"
+	case MetricEuclidean:
+		return "euclidean"
+	}
+	return fmt.Sprintf("Metric(%d)", uint8(m))
+}
+
+// Dot returns the dot product of a and b. It panics if their lengths differ.
+func Dot(a, b []float32) float32 {
+	mustSameLength(a, b)
+
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return float32(sum)
+}
+
+// Norm returns the L2 norm of v.
+func Norm(v []float32) float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	return float32(math.Sqrt(sum))
+}
+
+// Normalize returns a copy of v scaled to unit L2 norm. A zero vector is
+// returned unchanged.
+func Normalize(v []float32) []float32 {
+	out := make([]float32, len(v))
+	norm := Norm(v)
+	if norm == 0 {
+		copy(out, v)
+		return out
+	}
+	for i, x := range v {
+		out[i] = x / norm
+	}
+	return out
+}
+
+// Cosine returns the cosine similarity of a and b, or 0 if either is a zero
+// vector. It panics if their lengths differ.
+func Cosine(a, b []float32) float32 {
+	normA, normB := Norm(a), Norm(b)
+	if normA == 0 || normB == 0 {
+		mustSameLength(a, b)
+		return 0
+	}
+	return Dot(a, b) / (normA * normB)
+}
+
+// Euclidean returns the Euclidean distance between a and b. It panics if their
+// lengths differ.
+func Euclidean(a, b []float32) float32 {
+	mustSameLength(a, b)
+
+	var sum float64
+	for i := range a {
+		d := float64(a[i]) - float64(b[i])
+		sum += d * d
+	}
+	return float32(math.Sqrt(sum))
+}
+
+func mustSameLength(a, b []float32) {
+	if len(a) != len(b) {
+		panic(fmt.Sprintf("vector: length mismatch %d != %d", len(a), len(b)))
+	}
+}
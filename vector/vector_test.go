@@ -0,0 +1,49 @@
+package vector
+
+import (
+	"math"
+	"testing"
+)
+
+func almostEqual(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 1e-6
+}
+
+func TestMetrics(t *testing.T) {
+	a := []float32{1, 0, 0}
+	b := []float32{0, 1, 0}
+	c := []float32{2, 0, 0}
+
+	if got := Dot(a, c); got != 2 {
+		t.Errorf("Result was incorrect, got: %f, want: %d.", got, 2)
+	}
+	if got := Cosine(a, b); !almostEqual(got, 0) {
+		t.Errorf("Result was incorrect, got: %f, want: %d.", got, 0)
+	}
+	if got := Cosine(a, c); !almostEqual(got, 1) {
+		t.Errorf("Result was incorrect, got: %f, want: %d.", got, 1)
+	}
+	if got := Cosine(a, []float32{0, 0, 0}); got != 0 {
+		t.Errorf("Result was incorrect, got: %f, want: %d.", got, 0)
+	}
+	if got := Euclidean(a, b); !almostEqual(got, float32(math.Sqrt2)) {
+		t.Errorf("Result was incorrect, got: %f, want: %f.", got, math.Sqrt2)
+	}
+	if got := Norm([]float32{3, 4}); got != 5 {
+		t.Errorf("Result was incorrect, got: %f, want: %d.", got, 5)
+	}
+	if got := Normalize([]float32{3, 4}); !almostEqual(got[0], 0.6) || !almostEqual(got[1], 0.8) {
+		t.Errorf("Result was incorrect, got: %v, want: %v.", got, []float32{0.6, 0.8})
+	}
+	if MetricEuclidean.String() != "euclidean" {
+		t.Errorf("Result was incorrect, got: %s, want: %s.", MetricEuclidean, "euclidean")
+	}
+
+	// Case: Mismatched lengths panic
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Result was incorrect, got: no panic, want: panic.")
+		}
+	}()
+	Dot(a, []float32{1})
+}